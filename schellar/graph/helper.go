@@ -2,7 +2,6 @@ package graph
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -16,8 +15,12 @@ import (
 	"github.com/frinx/schellar/scheduler"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
+const defaultExecutionsPageSize = 100
+const maxPreviewCount = 100
+
 func ValidateName(name string) error {
 	if name == "" {
 		return errors.New("'name' is required")
@@ -37,24 +40,77 @@ func StringToStatusType(s string) model.Status {
 	return model.StatusUnknown
 }
 
+func StringToTriggerSourceType(s string) model.TriggerSource {
+	for _, trigger := range model.AllTriggerSource {
+		if string(trigger) == s {
+			return trigger
+		}
+	}
+	return model.TriggerSourceTimer
+}
+
 func ConvertIfcToModel(schedule_ifc *ifc.Schedule) *model.Schedule {
 
 	schedule_model := &model.Schedule{
-		Name:            schedule_ifc.Name,
-		Enabled:         schedule_ifc.Enabled,
-		ParallelRuns:    schedule_ifc.ParallelRuns,
-		WorkflowName:    schedule_ifc.WorkflowName,
-		WorkflowVersion: schedule_ifc.WorkflowVersion,
-		CronString:      schedule_ifc.CronString,
-		Status:          StringToStatusType(schedule_ifc.Status),
-		WorkflowContext: "",
-		FromDate:        "",
-		ToDate:          "",
+		Name:                schedule_ifc.Name,
+		Enabled:             schedule_ifc.Enabled,
+		ParallelRuns:        schedule_ifc.Concurrency() == ifc.ConcurrencyAllow,
+		ConcurrencyPolicy:   model.ConcurrencyPolicy(schedule_ifc.Concurrency()),
+		MaxConcurrentRuns:   schedule_ifc.MaxConcurrentRuns,
+		TriggerMode:         model.TriggerMode(schedule_ifc.TriggerMode),
+		DependsOn:           make([]*model.Dependency, 0, len(schedule_ifc.DependsOn)),
+		IncludeCalendars:    make([]string, 0, len(schedule_ifc.IncludeCalendars)),
+		ExcludeCalendars:    make([]string, 0, len(schedule_ifc.ExcludeCalendars)),
+		WorkflowName:        schedule_ifc.WorkflowName,
+		WorkflowVersion:     schedule_ifc.WorkflowVersion,
+		CronString:          schedule_ifc.CronString,
+		CronFormat:          model.CronFormat(schedule_ifc.CronFormat),
+		TimeZone:            schedule_ifc.TimeZone,
+		JitterSeconds:       schedule_ifc.JitterSeconds,
+		JitterMode:          model.JitterMode(schedule_ifc.JitterMode),
+		Status:              StringToStatusType(schedule_ifc.Status),
+		MisfirePolicy:       model.MisfirePolicy(schedule_ifc.MisfirePolicy),
+		MisfireMaxCount:     schedule_ifc.MisfireMaxCount,
+		WorkflowContext:     map[string]interface{}{},
+		OutputMapping:       map[string]interface{}{},
+		FromDate:            "",
+		ToDate:              "",
+		CorrelationID:       schedule_ifc.CorrelationID,
+		TaskToDomain:        map[string]interface{}{},
+		CheckWarningSeconds: schedule_ifc.CheckWarningSeconds,
+		Condition:           model.ScheduleCondition(schedule_ifc.Condition),
+		ConditionMessage:    schedule_ifc.ConditionMessage,
+		MaxRunDuration:      schedule_ifc.MaxRunDuration,
+		OnOverrun:           model.OverrunAction(schedule_ifc.OnOverrun),
+		RetryPolicy:         ConvertRetryPolicyToModel(schedule_ifc.RetryPolicy),
+		OnFailure:           model.FailureAction(schedule_ifc.OnFailure),
+		OnFailureMaxCount:   schedule_ifc.OnFailureMaxCount,
+		LastUpdate:          schedule_ifc.LastUpdate.Format(time.RFC3339),
+
+		UpdateContextOnFailure: schedule_ifc.UpdateContextOnFailure,
+		MaxContextBytes:        schedule_ifc.MaxContextBytes,
+		PausedBy:               schedule_ifc.PausedBy,
+		PauseReason:            schedule_ifc.PauseReason,
+		Labels:                 map[string]interface{}{},
 	}
 
 	if schedule_ifc.WorkflowContext != nil {
-		contextBytes, _ := json.Marshal(schedule_ifc.WorkflowContext)
-		schedule_model.WorkflowContext = string(contextBytes)
+		schedule_model.WorkflowContext = schedule_ifc.WorkflowContext
+	}
+
+	for task, domain := range schedule_ifc.TaskToDomain {
+		schedule_model.TaskToDomain[task] = domain
+	}
+
+	schedule_model.IncludeCalendars = append(schedule_model.IncludeCalendars, schedule_ifc.IncludeCalendars...)
+	schedule_model.ExcludeCalendars = append(schedule_model.ExcludeCalendars, schedule_ifc.ExcludeCalendars...)
+
+	for key, path := range schedule_ifc.OutputMapping {
+		schedule_model.OutputMapping[key] = path
+	}
+
+	for key, value := range schedule_ifc.Labels {
+		schedule_model.Labels[key] = value
 	}
 
 	if schedule_ifc.FromDate != nil {
@@ -65,17 +121,374 @@ func ConvertIfcToModel(schedule_ifc *ifc.Schedule) *model.Schedule {
 		schedule_model.ToDate = schedule_ifc.ToDate.Format(time.RFC3339)
 	}
 
+	if schedule_ifc.LastFireTime != nil {
+		lastFireTime := schedule_ifc.LastFireTime.Format(time.RFC3339)
+		schedule_model.LastFireTime = &lastFireTime
+	}
+
+	if schedule_ifc.NextFireTime != nil {
+		nextFireTime := schedule_ifc.NextFireTime.Format(time.RFC3339)
+		schedule_model.NextFireTime = &nextFireTime
+	}
+
+	if schedule_ifc.PausedAt != nil {
+		pausedAt := schedule_ifc.PausedAt.Format(time.RFC3339)
+		schedule_model.PausedAt = &pausedAt
+	}
+
+	if schedule_ifc.PausedUntil != nil {
+		pausedUntil := schedule_ifc.PausedUntil.Format(time.RFC3339)
+		schedule_model.PausedUntil = &pausedUntil
+	}
+
+	if !schedule_model.MisfirePolicy.IsValid() {
+		schedule_model.MisfirePolicy = model.MisfirePolicySkip
+	}
+
+	if !schedule_model.JitterMode.IsValid() {
+		schedule_model.JitterMode = model.JitterModeRandom
+	}
+
+	if !schedule_model.TriggerMode.IsValid() {
+		schedule_model.TriggerMode = model.TriggerModeCron
+	}
+
+	for _, dependency := range schedule_ifc.DependsOn {
+		schedule_model.DependsOn = append(schedule_model.DependsOn, &model.Dependency{
+			ScheduleName:  dependency.ScheduleName,
+			Condition:     model.DependencyCondition(dependency.Condition),
+			WithinMinutes: dependency.WithinMinutes,
+		})
+	}
+
+	if !schedule_model.OnFailure.IsValid() {
+		schedule_model.OnFailure = model.FailureActionNone
+	}
+
+	if !schedule_model.OnOverrun.IsValid() {
+		schedule_model.OnOverrun = model.OverrunActionNone
+	}
+
+	if !schedule_model.Condition.IsValid() {
+		schedule_model.Condition = model.ScheduleConditionOk
+	}
+
+	if !schedule_model.CronFormat.IsValid() {
+		schedule_model.CronFormat = model.CronFormatStandard
+	}
+
 	return schedule_model
 }
 
-func ConvertWorkflowContext(modelWorkflowContext string) (map[string]interface{}, error) {
+func ConvertRetryPolicyToModel(policy ifc.RetryPolicy) *model.RetryPolicy {
+	return &model.RetryPolicy{
+		MaxAttempts:         policy.Attempts(),
+		InitialDelaySeconds: policy.InitialDelaySeconds,
+		Multiplier:          policy.Multiplier,
+		MaxDelaySeconds:     policy.MaxDelaySeconds,
+	}
+}
 
-	var workflowContext map[string]interface{}
+func ConvertRetryPolicyInput(input *model.RetryPolicyInput) ifc.RetryPolicy {
+	policy := ifc.RetryPolicy{
+		MaxAttempts: input.MaxAttempts,
+	}
+	if input.InitialDelaySeconds != nil {
+		policy.InitialDelaySeconds = *input.InitialDelaySeconds
+	}
+	if input.Multiplier != nil {
+		policy.Multiplier = *input.Multiplier
+	}
+	if input.MaxDelaySeconds != nil {
+		policy.MaxDelaySeconds = *input.MaxDelaySeconds
+	}
+	return policy
+}
 
-	if modelWorkflowContext != "" {
-		json.Unmarshal([]byte(modelWorkflowContext), &workflowContext)
+func ConvertDependencyInputs(inputs []*model.DependencyInput) []ifc.Dependency {
+	dependencies := make([]ifc.Dependency, 0, len(inputs))
+	for _, input := range inputs {
+		dependency := ifc.Dependency{
+			ScheduleName: input.ScheduleName,
+		}
+		if input.Condition != nil {
+			dependency.Condition = input.Condition.String()
+		}
+		if input.WithinMinutes != nil {
+			dependency.WithinMinutes = *input.WithinMinutes
+		}
+		dependencies = append(dependencies, dependency)
 	}
-	return workflowContext, nil
+	return dependencies
+}
+
+// ValidateDependencies checks that schedules the schedule depends on exist and do not depend back on it
+func ValidateDependencies(schedule *ifc.Schedule) error {
+	visited := make(map[string]bool)
+	var visit func(dependencies []ifc.Dependency, path string) error
+	visit = func(dependencies []ifc.Dependency, path string) error {
+		for _, dependency := range dependencies {
+			dependencyPath := path + " -> " + dependency.ScheduleName
+			if dependency.ScheduleName == schedule.Name {
+				return fmt.Errorf("dependency cycle %s", dependencyPath)
+			}
+			if visited[dependency.ScheduleName] {
+				continue
+			}
+			visited[dependency.ScheduleName] = true
+			upstream, err := scheduler.Configuration.Db.FindByName(dependency.ScheduleName)
+			if err != nil {
+				return fmt.Errorf("cannot get schedule %s. err=%v", dependency.ScheduleName, err)
+			}
+			if upstream == nil {
+				if path == schedule.Name {
+					return fmt.Errorf("dependency schedule %s not found", dependency.ScheduleName)
+				}
+				// dangling dependency of another schedule, just never satisfied
+				continue
+			}
+			err = visit(upstream.DependsOn, dependencyPath)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(schedule.DependsOn, schedule.Name)
+}
+
+// ValidateCalendars checks that calendars included or excluded by the schedule exist
+func ValidateCalendars(schedule *ifc.Schedule) error {
+	for _, name := range schedule.Calendars() {
+		calendar, err := scheduler.Configuration.Db.FindCalendarByName(name)
+		if err != nil {
+			return fmt.Errorf("cannot get calendar %s. err=%v", name, err)
+		}
+		if calendar == nil {
+			return fmt.Errorf("calendar %s not found", name)
+		}
+	}
+	return nil
+}
+
+func ConvertCalendarToModel(calendar_ifc *ifc.Calendar) *model.Calendar {
+
+	calendar_model := &model.Calendar{
+		Name:          calendar_ifc.Name,
+		Description:   calendar_ifc.Description,
+		TimeZone:      calendar_ifc.TimeZone,
+		DateRanges:    make([]*model.DateRange, 0, len(calendar_ifc.DateRanges)),
+		WeeklyWindows: make([]*model.WeeklyWindow, 0, len(calendar_ifc.WeeklyWindows)),
+		LastUpdate:    calendar_ifc.LastUpdate.Format(time.RFC3339),
+	}
+
+	for _, dateRange := range calendar_ifc.DateRanges {
+		calendar_model.DateRanges = append(calendar_model.DateRanges, &model.DateRange{
+			From:        dateRange.From.Format(time.RFC3339),
+			To:          dateRange.To.Format(time.RFC3339),
+			Description: dateRange.Description,
+		})
+	}
+
+	for _, window := range calendar_ifc.WeeklyWindows {
+		days := make([]model.Weekday, 0, len(window.Days))
+		for _, day := range window.Days {
+			days = append(days, model.Weekday(day))
+		}
+		calendar_model.WeeklyWindows = append(calendar_model.WeeklyWindows, &model.WeeklyWindow{
+			Days:      days,
+			StartTime: window.StartTime,
+			EndTime:   window.EndTime,
+		})
+	}
+
+	return calendar_model
+}
+
+func ConvertDateRangeInputs(inputs []*model.DateRangeInput) ([]ifc.DateRange, error) {
+	dateRanges := make([]ifc.DateRange, 0, len(inputs))
+	for _, input := range inputs {
+		from, err := ConvertDateTime(input.From)
+		if err != nil {
+			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
+		}
+		to, err := ConvertDateTime(input.To)
+		if err != nil {
+			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
+		}
+		dateRange := ifc.DateRange{From: from, To: to}
+		if input.Description != nil {
+			dateRange.Description = *input.Description
+		}
+		dateRanges = append(dateRanges, dateRange)
+	}
+	return dateRanges, nil
+}
+
+func ConvertWeeklyWindowInputs(inputs []*model.WeeklyWindowInput) []ifc.WeeklyWindow {
+	windows := make([]ifc.WeeklyWindow, 0, len(inputs))
+	for _, input := range inputs {
+		days := make([]string, 0, len(input.Days))
+		for _, day := range input.Days {
+			days = append(days, day.String())
+		}
+		windows = append(windows, ifc.WeeklyWindow{Days: days, StartTime: input.StartTime, EndTime: input.EndTime})
+	}
+	return windows
+}
+
+// ImportICal appends events of iCalendar document to date ranges of the calendar
+func ImportICal(calendar *ifc.Calendar, ical string) error {
+	location, err := calendar.Location()
+	if err != nil {
+		return fmt.Errorf("'timeZone' is invalid. err=%v", err)
+	}
+	dateRanges, err := ifc.ParseICal(ical, location)
+	if err != nil {
+		return fmt.Errorf("'ical' is invalid. err=%v", err)
+	}
+	calendar.DateRanges = append(calendar.DateRanges, dateRanges...)
+	return nil
+}
+
+func ConvertExecutionToModel(execution_ifc *ifc.Execution) *model.Execution {
+
+	execution_model := &model.Execution{
+		ID:           execution_ifc.ID,
+		ScheduleName: execution_ifc.ScheduleName,
+		FireTime:     execution_ifc.FireTime.Format(time.RFC3339),
+		Trigger:      StringToTriggerSourceType(execution_ifc.Trigger),
+		WorkflowID:   execution_ifc.WorkflowID,
+		Status:       StringToStatusType(execution_ifc.Status),
+		Attempts:     execution_ifc.Attempts,
+		Recoveries:   execution_ifc.Recoveries,
+		WorkflowName: execution_ifc.WorkflowName,
+	}
+
+	if execution_model.Attempts == 0 {
+		// executions recorded before launch retries were introduced
+		execution_model.Attempts = 1
+	}
+
+	if execution_ifc.StartTime != nil {
+		startTime := execution_ifc.StartTime.Format(time.RFC3339)
+		execution_model.StartTime = &startTime
+	}
+
+	if execution_ifc.EndTime != nil {
+		endTime := execution_ifc.EndTime.Format(time.RFC3339)
+		execution_model.EndTime = &endTime
+	}
+
+	if execution_ifc.Error != "" {
+		executionError := execution_ifc.Error
+		execution_model.Error = &executionError
+	}
+
+	return execution_model
+}
+
+// ConvertTaskToDomain checks that all domains in taskToDomain JSON are strings
+func ConvertTaskToDomain(modelTaskToDomain map[string]interface{}) (map[string]string, error) {
+
+	taskToDomain := make(map[string]string)
+	for task, domain := range modelTaskToDomain {
+		domainString, ok := domain.(string)
+		if !ok {
+			return nil, fmt.Errorf("'taskToDomain' value of task '%s' has to be a string", task)
+		}
+		taskToDomain[task] = domainString
+	}
+	return taskToDomain, nil
+}
+
+// ConvertLabels checks that all values in labels JSON are strings
+func ConvertLabels(modelLabels map[string]interface{}) (map[string]string, error) {
+
+	labels := make(map[string]string)
+	for key, value := range modelLabels {
+		valueString, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("'labels' value of key '%s' has to be a string", key)
+		}
+		labels[key] = valueString
+	}
+	return labels, nil
+}
+
+// ConvertOutputMapping checks that all paths in outputMapping JSON are strings
+func ConvertOutputMapping(modelOutputMapping map[string]interface{}) (map[string]string, error) {
+
+	outputMapping := make(map[string]string)
+	for key, path := range modelOutputMapping {
+		pathString, ok := path.(string)
+		if !ok {
+			return nil, fmt.Errorf("'outputMapping' value of key '%s' has to be a string", key)
+		}
+		outputMapping[key] = pathString
+	}
+	return outputMapping, nil
+}
+
+func ConvertContextVersionToModel(version_ifc *ifc.ContextVersion) *model.ContextVersion {
+
+	version_model := &model.ContextVersion{
+		ScheduleName:    version_ifc.ScheduleName,
+		Version:         version_ifc.Version,
+		WorkflowContext: version_ifc.WorkflowContext,
+		Source:          model.ContextSource(version_ifc.Source),
+		WorkflowID:      version_ifc.WorkflowID,
+		Created:         version_ifc.Created.Format(time.RFC3339),
+	}
+
+	if version_model.WorkflowContext == nil {
+		version_model.WorkflowContext = map[string]interface{}{}
+	}
+
+	return version_model
+}
+
+// PauseSchedule pauses the schedule by user until the optional end and stores it
+func PauseSchedule(schedule *ifc.Schedule, user string, reason *string, until *string) error {
+	var untilTime *time.Time
+	if until != nil {
+		parsed, err := ConvertDateTime(*until)
+		if err != nil {
+			return fmt.Errorf("'until' is invalid. err=%v", err)
+		}
+		untilTime = &parsed
+	}
+	pauseReason := ""
+	if reason != nil {
+		pauseReason = *reason
+	}
+	now := time.Now()
+	err := schedule.Pause(user, pauseReason, untilTime, now)
+	if err != nil {
+		return err
+	}
+	schedule.LastUpdate = now
+	return scheduler.Configuration.Db.Update(*schedule)
+}
+
+// ResumeSchedule enables the paused schedule and stores it
+func ResumeSchedule(schedule *ifc.Schedule) error {
+	now := time.Now()
+	schedule.Resume(now)
+	schedule.LastUpdate = now
+	return scheduler.Configuration.Db.Update(*schedule)
+}
+
+// FindSchedulesBySelector returns schedules matching all criteria of the selector, at least one is required
+func FindSchedulesBySelector(selector model.ScheduleSelectorInput) ([]ifc.Schedule, error) {
+	filter, err := ConvertScheduleFilter(selector.WorkflowName, nil, selector.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	if filter.WorkflowName == "" && len(filter.LabelSelector) == 0 {
+		return nil, errors.New("'selector' has to contain at least one criterion")
+	}
+	return scheduler.Configuration.Db.FindSchedules(filter)
 }
 
 func ConvertDateTime(modelDateTime string) (time.Time, error) {
@@ -89,22 +502,174 @@ func ConvertDateTime(modelDateTime string) (time.Time, error) {
 	return dateFrom, err
 }
 
-func GetSchedules() []*model.Schedule {
-	ifc_schedules, _ := scheduler.Configuration.Db.FindAll()
-	model_schedules := make([]*model.Schedule, len(ifc_schedules))
-	for i, v := range ifc_schedules {
-		model_schedules[i] = ConvertIfcToModel(&v)
+// ConvertModelToCronSchedule creates schedule with fields needed to compute its fire times
+func ConvertModelToCronSchedule(schedule_model *model.Schedule) (*ifc.Schedule, error) {
+
+	schedule_ifc := &ifc.Schedule{
+		Name:             schedule_model.Name,
+		Enabled:          schedule_model.Enabled,
+		CronString:       schedule_model.CronString,
+		CronFormat:       schedule_model.CronFormat.String(),
+		TimeZone:         schedule_model.TimeZone,
+		TriggerMode:      schedule_model.TriggerMode.String(),
+		JitterSeconds:    schedule_model.JitterSeconds,
+		JitterMode:       schedule_model.JitterMode.String(),
+		IncludeCalendars: schedule_model.IncludeCalendars,
+		ExcludeCalendars: schedule_model.ExcludeCalendars,
+	}
+
+	if schedule_model.FromDate != "" {
+		fromDate, err := ConvertDateTime(schedule_model.FromDate)
+		if err != nil {
+			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
+		}
+		schedule_ifc.FromDate = &fromDate
+	}
+
+	if schedule_model.ToDate != "" {
+		toDate, err := ConvertDateTime(schedule_model.ToDate)
+		if err != nil {
+			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
+		}
+		schedule_ifc.ToDate = &toDate
+	}
+
+	return schedule_ifc, nil
+}
+
+// FormatFireTimes formats fire times in the time zone of the schedule
+func FormatFireTimes(schedule *ifc.Schedule, fireTimes []time.Time) []string {
+	location, err := schedule.Location()
+	if err != nil {
+		location = time.Local
+	}
+	formatted := make([]string, len(fireTimes))
+	for i, fireTime := range fireTimes {
+		formatted[i] = fireTime.In(location).Format(time.RFC3339)
+	}
+	return formatted
+}
+
+func getPreviewCount(count *int) (int, error) {
+	if count == nil {
+		return 5, nil
+	}
+	if *count <= 0 {
+		return 0, errors.New("'count' has to be positive")
+	}
+	if *count > maxPreviewCount {
+		return 0, fmt.Errorf("'count' cannot be greater than %d", maxPreviewCount)
+	}
+	return *count, nil
+}
+
+// shouldValidateWorkflow returns true unless workflow validation is disabled globally or for the request
+func shouldValidateWorkflow(skipWorkflowValidation *bool) bool {
+	if skipWorkflowValidation != nil && *skipWorkflowValidation {
+		return false
 	}
-	return model_schedules
+	return scheduler.Configuration.ValidateWorkflows
 }
 
-func GetSchedulesFilter(filter *model.SchedulesFilterInput) []*model.Schedule {
-	ifc_schedules, _ := scheduler.Configuration.Db.FindAllByWorkflowType(filter.WorkflowName, filter.WorkflowVersion)
+// ConvertValidationError adds code and field of workflow validation error to GraphQL error extensions
+func ConvertValidationError(ctx context.Context, err error) error {
+	var validationErr *scheduler.WorkflowValidationError
+	if !errors.As(err, &validationErr) {
+		return fmt.Errorf("Error validating schedule %s", err)
+	}
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("Error validating schedule %s", validationErr.Message),
+		Path:    graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{
+			"code":  validationErr.Code,
+			"field": validationErr.Field,
+		},
+	}
+}
+
+// GetSchedulesFilter returns schedules matching the optional filter, filtered and sorted by the backend
+func GetSchedulesFilter(filter *model.SchedulesFilterInput, orderBy *model.ScheduleOrderInput) ([]*model.Schedule, error) {
+	scheduleFilter, err := ConvertSchedulesFilterInput(filter)
+	if err != nil {
+		return nil, err
+	}
+	if orderBy != nil {
+		scheduleFilter.SortBy = orderBy.Field.String()
+		scheduleFilter.SortDescending = orderBy.Direction != nil && *orderBy.Direction == model.SortDirectionDesc
+	}
+	ifc_schedules, err := scheduler.Configuration.Db.FindSchedules(scheduleFilter)
+	if err != nil {
+		return nil, err
+	}
 	model_schedules := make([]*model.Schedule, len(ifc_schedules))
 	for i, v := range ifc_schedules {
 		model_schedules[i] = ConvertIfcToModel(&v)
 	}
-	return model_schedules
+	return model_schedules, nil
+}
+
+// ConvertSchedulesFilterInput builds filter of schedules from the schedules query filter, nil filter matches all schedules
+func ConvertSchedulesFilterInput(filter *model.SchedulesFilterInput) (ifc.ScheduleFilter, error) {
+	if filter == nil {
+		return ifc.ScheduleFilter{}, nil
+	}
+	scheduleFilter, err := ConvertScheduleFilter(filter.WorkflowName, filter.WorkflowVersion, filter.LabelSelector)
+	if err != nil {
+		return scheduleFilter, err
+	}
+	if filter.NamePrefix != nil {
+		scheduleFilter.NamePrefix = *filter.NamePrefix
+	}
+	if filter.NameContains != nil {
+		scheduleFilter.NameContains = *filter.NameContains
+	}
+	scheduleFilter.Enabled = filter.Enabled
+	if filter.Status != nil {
+		scheduleFilter.Status = filter.Status.String()
+	}
+	if filter.CronString != nil {
+		scheduleFilter.CronString = *filter.CronString
+	}
+	scheduleFilter.ActiveAt, err = convertOptionalDateTime("activeAt", filter.ActiveAt)
+	if err != nil {
+		return scheduleFilter, err
+	}
+	scheduleFilter.LastUpdateAfter, err = convertOptionalDateTime("lastUpdateAfter", filter.LastUpdateAfter)
+	if err != nil {
+		return scheduleFilter, err
+	}
+	scheduleFilter.LastUpdateBefore, err = convertOptionalDateTime("lastUpdateBefore", filter.LastUpdateBefore)
+	return scheduleFilter, err
+}
+
+func convertOptionalDateTime(field string, modelDateTime *string) (*time.Time, error) {
+	if modelDateTime == nil {
+		return nil, nil
+	}
+	dateTime, err := ConvertDateTime(*modelDateTime)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is invalid. err=%v", field, err)
+	}
+	return &dateTime, nil
+}
+
+// ConvertScheduleFilter builds filter of schedules from optional criteria
+func ConvertScheduleFilter(workflowName *string, workflowVersion *string, labelSelector *string) (ifc.ScheduleFilter, error) {
+	filter := ifc.ScheduleFilter{}
+	if workflowName != nil {
+		filter.WorkflowName = *workflowName
+	}
+	if workflowVersion != nil {
+		filter.WorkflowVersion = *workflowVersion
+	}
+	if labelSelector != nil {
+		selector, err := ifc.ParseLabelSelector(*labelSelector)
+		if err != nil {
+			return filter, fmt.Errorf("'labelSelector' is invalid. err=%v", err)
+		}
+		filter.LabelSelector = selector
+	}
+	return filter, nil
 }
 
 func getScheduleAfterCursor(cursor string, schedules []*model.Schedule) ([]*model.Schedule, int) {
@@ -269,6 +834,11 @@ func extractUserHeader(ctx context.Context) error {
 	return nil
 }
 
+// getUserHeader returns the user from the From header, checked by the server for every operation
+func getUserHeader(ctx context.Context) string {
+	return graphql.GetOperationContext(ctx).Headers.Get("from")
+}
+
 func getAdminValues() []string {
 
 	adminRoles := []string{os.Getenv("ADMIN_ROLES"), os.Getenv("ADMIN_GROUPS")}
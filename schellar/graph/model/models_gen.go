@@ -8,16 +8,119 @@ import (
 	"strconv"
 )
 
+type Calendar struct {
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	TimeZone      string          `json:"timeZone"`
+	DateRanges    []*DateRange    `json:"dateRanges"`
+	WeeklyWindows []*WeeklyWindow `json:"weeklyWindows"`
+	LastUpdate    string          `json:"lastUpdate"`
+	Contains      bool            `json:"contains"`
+}
+
+type ContextVersion struct {
+	ScheduleName    string                 `json:"scheduleName"`
+	Version         int                    `json:"version"`
+	WorkflowContext map[string]interface{} `json:"workflowContext"`
+	Source          ContextSource          `json:"source"`
+	WorkflowID      string                 `json:"workflowId"`
+	Created         string                 `json:"created"`
+}
+
+type CreateCalendarInput struct {
+	Name          string               `json:"name"`
+	Description   *string              `json:"description,omitempty"`
+	TimeZone      *string              `json:"timeZone,omitempty"`
+	DateRanges    []*DateRangeInput    `json:"dateRanges,omitempty"`
+	WeeklyWindows []*WeeklyWindowInput `json:"weeklyWindows,omitempty"`
+	Ical          *string              `json:"ical,omitempty"`
+}
+
 type CreateScheduleInput struct {
-	Name            string  `json:"name"`
-	WorkflowName    string  `json:"workflowName"`
-	WorkflowVersion string  `json:"workflowVersion"`
-	CronString      string  `json:"cronString"`
-	Enabled         *bool   `json:"enabled,omitempty"`
-	ParallelRuns    *bool   `json:"parallelRuns,omitempty"`
-	WorkflowContext *string `json:"workflowContext,omitempty"`
-	FromDate        *string `json:"fromDate,omitempty"`
-	ToDate          *string `json:"toDate,omitempty"`
+	Name                   string                 `json:"name"`
+	WorkflowName           string                 `json:"workflowName"`
+	WorkflowVersion        string                 `json:"workflowVersion"`
+	CronString             *string                `json:"cronString,omitempty"`
+	CronFormat             *CronFormat            `json:"cronFormat,omitempty"`
+	TimeZone               *string                `json:"timeZone,omitempty"`
+	JitterSeconds          *int                   `json:"jitterSeconds,omitempty"`
+	JitterMode             *JitterMode            `json:"jitterMode,omitempty"`
+	Enabled                *bool                  `json:"enabled,omitempty"`
+	ParallelRuns           *bool                  `json:"parallelRuns,omitempty"`
+	ConcurrencyPolicy      *ConcurrencyPolicy     `json:"concurrencyPolicy,omitempty"`
+	MaxConcurrentRuns      *int                   `json:"maxConcurrentRuns,omitempty"`
+	TriggerMode            *TriggerMode           `json:"triggerMode,omitempty"`
+	DependsOn              []*DependencyInput     `json:"dependsOn,omitempty"`
+	IncludeCalendars       []string               `json:"includeCalendars,omitempty"`
+	ExcludeCalendars       []string               `json:"excludeCalendars,omitempty"`
+	WorkflowContext        map[string]interface{} `json:"workflowContext,omitempty"`
+	OutputMapping          map[string]interface{} `json:"outputMapping,omitempty"`
+	UpdateContextOnFailure *bool                  `json:"updateContextOnFailure,omitempty"`
+	MaxContextBytes        *int                   `json:"maxContextBytes,omitempty"`
+	FromDate               *string                `json:"fromDate,omitempty"`
+	ToDate                 *string                `json:"toDate,omitempty"`
+	MisfirePolicy          *MisfirePolicy         `json:"misfirePolicy,omitempty"`
+	MisfireMaxCount        *int                   `json:"misfireMaxCount,omitempty"`
+	CorrelationID          *string                `json:"correlationId,omitempty"`
+	TaskToDomain           map[string]interface{} `json:"taskToDomain,omitempty"`
+	Labels                 map[string]interface{} `json:"labels,omitempty"`
+	CheckWarningSeconds    *int                   `json:"checkWarningSeconds,omitempty"`
+	MaxRunDuration         *int                   `json:"maxRunDuration,omitempty"`
+	OnOverrun              *OverrunAction         `json:"onOverrun,omitempty"`
+	RetryPolicy            *RetryPolicyInput      `json:"retryPolicy,omitempty"`
+	OnFailure              *FailureAction         `json:"onFailure,omitempty"`
+	OnFailureMaxCount      *int                   `json:"onFailureMaxCount,omitempty"`
+	SkipWorkflowValidation *bool                  `json:"skipWorkflowValidation,omitempty"`
+}
+
+type DateRange struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Description string `json:"description"`
+}
+
+type DateRangeInput struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Description *string `json:"description,omitempty"`
+}
+
+type Dependency struct {
+	ScheduleName  string              `json:"scheduleName"`
+	Condition     DependencyCondition `json:"condition"`
+	WithinMinutes int                 `json:"withinMinutes"`
+}
+
+type DependencyInput struct {
+	ScheduleName  string               `json:"scheduleName"`
+	Condition     *DependencyCondition `json:"condition,omitempty"`
+	WithinMinutes *int                 `json:"withinMinutes,omitempty"`
+}
+
+type Execution struct {
+	ID           string        `json:"id"`
+	ScheduleName string        `json:"scheduleName"`
+	FireTime     string        `json:"fireTime"`
+	Trigger      TriggerSource `json:"trigger"`
+	WorkflowID   string        `json:"workflowId"`
+	Status       Status        `json:"status"`
+	StartTime    *string       `json:"startTime,omitempty"`
+	EndTime      *string       `json:"endTime,omitempty"`
+	Error        *string       `json:"error,omitempty"`
+	Attempts     int           `json:"attempts"`
+	Recoveries   int           `json:"recoveries"`
+	WorkflowName string        `json:"workflowName"`
+}
+
+type ExecutionConnection struct {
+	Edges      []*ExecutionEdge `json:"edges"`
+	PageInfo   *PageInfo        `json:"pageInfo"`
+	TotalCount int              `json:"totalCount"`
+}
+
+type ExecutionEdge struct {
+	Node   *Execution `json:"node"`
+	Cursor string     `json:"cursor"`
 }
 
 type Mutation struct {
@@ -33,17 +136,65 @@ type PageInfo struct {
 type Query struct {
 }
 
+type RetryPolicy struct {
+	MaxAttempts         int     `json:"maxAttempts"`
+	InitialDelaySeconds int     `json:"initialDelaySeconds"`
+	Multiplier          float64 `json:"multiplier"`
+	MaxDelaySeconds     int     `json:"maxDelaySeconds"`
+}
+
+type RetryPolicyInput struct {
+	MaxAttempts         int      `json:"maxAttempts"`
+	InitialDelaySeconds *int     `json:"initialDelaySeconds,omitempty"`
+	Multiplier          *float64 `json:"multiplier,omitempty"`
+	MaxDelaySeconds     *int     `json:"maxDelaySeconds,omitempty"`
+}
+
 type Schedule struct {
-	Name            string `json:"name"`
-	Enabled         bool   `json:"enabled"`
-	ParallelRuns    bool   `json:"parallelRuns"`
-	WorkflowName    string `json:"workflowName"`
-	WorkflowVersion string `json:"workflowVersion"`
-	CronString      string `json:"cronString"`
-	WorkflowContext string `json:"workflowContext"`
-	FromDate        string `json:"fromDate"`
-	ToDate          string `json:"toDate"`
-	Status          Status `json:"status"`
+	Name                   string                 `json:"name"`
+	Enabled                bool                   `json:"enabled"`
+	ParallelRuns           bool                   `json:"parallelRuns"`
+	ConcurrencyPolicy      ConcurrencyPolicy      `json:"concurrencyPolicy"`
+	MaxConcurrentRuns      int                    `json:"maxConcurrentRuns"`
+	TriggerMode            TriggerMode            `json:"triggerMode"`
+	DependsOn              []*Dependency          `json:"dependsOn"`
+	IncludeCalendars       []string               `json:"includeCalendars"`
+	ExcludeCalendars       []string               `json:"excludeCalendars"`
+	WorkflowName           string                 `json:"workflowName"`
+	WorkflowVersion        string                 `json:"workflowVersion"`
+	CronString             string                 `json:"cronString"`
+	CronFormat             CronFormat             `json:"cronFormat"`
+	TimeZone               string                 `json:"timeZone"`
+	JitterSeconds          int                    `json:"jitterSeconds"`
+	JitterMode             JitterMode             `json:"jitterMode"`
+	WorkflowContext        map[string]interface{} `json:"workflowContext"`
+	OutputMapping          map[string]interface{} `json:"outputMapping"`
+	UpdateContextOnFailure bool                   `json:"updateContextOnFailure"`
+	MaxContextBytes        int                    `json:"maxContextBytes"`
+	FromDate               string                 `json:"fromDate"`
+	ToDate                 string                 `json:"toDate"`
+	Status                 Status                 `json:"status"`
+	CorrelationID          string                 `json:"correlationId"`
+	TaskToDomain           map[string]interface{} `json:"taskToDomain"`
+	Labels                 map[string]interface{} `json:"labels"`
+	CheckWarningSeconds    int                    `json:"checkWarningSeconds"`
+	Condition              ScheduleCondition      `json:"condition"`
+	ConditionMessage       string                 `json:"conditionMessage"`
+	MaxRunDuration         int                    `json:"maxRunDuration"`
+	OnOverrun              OverrunAction          `json:"onOverrun"`
+	RetryPolicy            *RetryPolicy           `json:"retryPolicy"`
+	OnFailure              FailureAction          `json:"onFailure"`
+	OnFailureMaxCount      int                    `json:"onFailureMaxCount"`
+	LastUpdate             string                 `json:"lastUpdate"`
+	MisfirePolicy          MisfirePolicy          `json:"misfirePolicy"`
+	MisfireMaxCount        int                    `json:"misfireMaxCount"`
+	LastFireTime           *string                `json:"lastFireTime,omitempty"`
+	NextFireTime           *string                `json:"nextFireTime,omitempty"`
+	PausedBy               string                 `json:"pausedBy"`
+	PauseReason            string                 `json:"pauseReason"`
+	PausedAt               *string                `json:"pausedAt,omitempty"`
+	PausedUntil            *string                `json:"pausedUntil,omitempty"`
+	NextRuns               []string               `json:"nextRuns"`
 }
 
 type ScheduleConnection struct {
@@ -57,20 +208,557 @@ type ScheduleEdge struct {
 	Cursor string    `json:"cursor"`
 }
 
+type ScheduleOrderInput struct {
+	Field     ScheduleSortField `json:"field"`
+	Direction *SortDirection    `json:"direction,omitempty"`
+}
+
+type ScheduleSelectorInput struct {
+	WorkflowName  *string `json:"workflowName,omitempty"`
+	LabelSelector *string `json:"labelSelector,omitempty"`
+}
+
 type SchedulesFilterInput struct {
-	WorkflowName    string `json:"workflowName"`
-	WorkflowVersion string `json:"workflowVersion"`
+	WorkflowName     *string `json:"workflowName,omitempty"`
+	WorkflowVersion  *string `json:"workflowVersion,omitempty"`
+	LabelSelector    *string `json:"labelSelector,omitempty"`
+	NamePrefix       *string `json:"namePrefix,omitempty"`
+	NameContains     *string `json:"nameContains,omitempty"`
+	Enabled          *bool   `json:"enabled,omitempty"`
+	Status           *Status `json:"status,omitempty"`
+	CronString       *string `json:"cronString,omitempty"`
+	ActiveAt         *string `json:"activeAt,omitempty"`
+	LastUpdateAfter  *string `json:"lastUpdateAfter,omitempty"`
+	LastUpdateBefore *string `json:"lastUpdateBefore,omitempty"`
+}
+
+type UpdateCalendarInput struct {
+	Description   *string              `json:"description,omitempty"`
+	TimeZone      *string              `json:"timeZone,omitempty"`
+	DateRanges    []*DateRangeInput    `json:"dateRanges,omitempty"`
+	WeeklyWindows []*WeeklyWindowInput `json:"weeklyWindows,omitempty"`
+	Ical          *string              `json:"ical,omitempty"`
 }
 
 type UpdateScheduleInput struct {
-	WorkflowName    *string `json:"workflowName,omitempty"`
-	WorkflowVersion *string `json:"workflowVersion,omitempty"`
-	CronString      *string `json:"cronString,omitempty"`
-	Enabled         *bool   `json:"enabled,omitempty"`
-	ParallelRuns    *bool   `json:"parallelRuns,omitempty"`
-	WorkflowContext *string `json:"workflowContext,omitempty"`
-	FromDate        *string `json:"fromDate,omitempty"`
-	ToDate          *string `json:"toDate,omitempty"`
+	WorkflowName           *string                `json:"workflowName,omitempty"`
+	WorkflowVersion        *string                `json:"workflowVersion,omitempty"`
+	CronString             *string                `json:"cronString,omitempty"`
+	CronFormat             *CronFormat            `json:"cronFormat,omitempty"`
+	TimeZone               *string                `json:"timeZone,omitempty"`
+	JitterSeconds          *int                   `json:"jitterSeconds,omitempty"`
+	JitterMode             *JitterMode            `json:"jitterMode,omitempty"`
+	Enabled                *bool                  `json:"enabled,omitempty"`
+	ParallelRuns           *bool                  `json:"parallelRuns,omitempty"`
+	ConcurrencyPolicy      *ConcurrencyPolicy     `json:"concurrencyPolicy,omitempty"`
+	MaxConcurrentRuns      *int                   `json:"maxConcurrentRuns,omitempty"`
+	TriggerMode            *TriggerMode           `json:"triggerMode,omitempty"`
+	DependsOn              []*DependencyInput     `json:"dependsOn,omitempty"`
+	IncludeCalendars       []string               `json:"includeCalendars,omitempty"`
+	ExcludeCalendars       []string               `json:"excludeCalendars,omitempty"`
+	WorkflowContext        map[string]interface{} `json:"workflowContext,omitempty"`
+	OutputMapping          map[string]interface{} `json:"outputMapping,omitempty"`
+	UpdateContextOnFailure *bool                  `json:"updateContextOnFailure,omitempty"`
+	MaxContextBytes        *int                   `json:"maxContextBytes,omitempty"`
+	FromDate               *string                `json:"fromDate,omitempty"`
+	ToDate                 *string                `json:"toDate,omitempty"`
+	MisfirePolicy          *MisfirePolicy         `json:"misfirePolicy,omitempty"`
+	MisfireMaxCount        *int                   `json:"misfireMaxCount,omitempty"`
+	CorrelationID          *string                `json:"correlationId,omitempty"`
+	TaskToDomain           map[string]interface{} `json:"taskToDomain,omitempty"`
+	Labels                 map[string]interface{} `json:"labels,omitempty"`
+	CheckWarningSeconds    *int                   `json:"checkWarningSeconds,omitempty"`
+	MaxRunDuration         *int                   `json:"maxRunDuration,omitempty"`
+	OnOverrun              *OverrunAction         `json:"onOverrun,omitempty"`
+	RetryPolicy            *RetryPolicyInput      `json:"retryPolicy,omitempty"`
+	OnFailure              *FailureAction         `json:"onFailure,omitempty"`
+	OnFailureMaxCount      *int                   `json:"onFailureMaxCount,omitempty"`
+	SkipWorkflowValidation *bool                  `json:"skipWorkflowValidation,omitempty"`
+}
+
+type WeeklyWindow struct {
+	Days      []Weekday `json:"days"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+}
+
+type WeeklyWindowInput struct {
+	Days      []Weekday `json:"days"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+}
+
+type ConcurrencyPolicy string
+
+const (
+	ConcurrencyPolicyAllow   ConcurrencyPolicy = "ALLOW"
+	ConcurrencyPolicyForbid  ConcurrencyPolicy = "FORBID"
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "REPLACE"
+	ConcurrencyPolicyQueue   ConcurrencyPolicy = "QUEUE"
+)
+
+var AllConcurrencyPolicy = []ConcurrencyPolicy{
+	ConcurrencyPolicyAllow,
+	ConcurrencyPolicyForbid,
+	ConcurrencyPolicyReplace,
+	ConcurrencyPolicyQueue,
+}
+
+func (e ConcurrencyPolicy) IsValid() bool {
+	switch e {
+	case ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace, ConcurrencyPolicyQueue:
+		return true
+	}
+	return false
+}
+
+func (e ConcurrencyPolicy) String() string {
+	return string(e)
+}
+
+func (e *ConcurrencyPolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ConcurrencyPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ConcurrencyPolicy", str)
+	}
+	return nil
+}
+
+func (e ConcurrencyPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ContextSource string
+
+const (
+	ContextSourceWorkflowOutput ContextSource = "WORKFLOW_OUTPUT"
+	ContextSourceUpdate         ContextSource = "UPDATE"
+	ContextSourceRestore        ContextSource = "RESTORE"
+)
+
+var AllContextSource = []ContextSource{
+	ContextSourceWorkflowOutput,
+	ContextSourceUpdate,
+	ContextSourceRestore,
+}
+
+func (e ContextSource) IsValid() bool {
+	switch e {
+	case ContextSourceWorkflowOutput, ContextSourceUpdate, ContextSourceRestore:
+		return true
+	}
+	return false
+}
+
+func (e ContextSource) String() string {
+	return string(e)
+}
+
+func (e *ContextSource) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ContextSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ContextSource", str)
+	}
+	return nil
+}
+
+func (e ContextSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CronFormat string
+
+const (
+	CronFormatStandard    CronFormat = "STANDARD"
+	CronFormatWithSeconds CronFormat = "WITH_SECONDS"
+	CronFormatDescriptor  CronFormat = "DESCRIPTOR"
+)
+
+var AllCronFormat = []CronFormat{
+	CronFormatStandard,
+	CronFormatWithSeconds,
+	CronFormatDescriptor,
+}
+
+func (e CronFormat) IsValid() bool {
+	switch e {
+	case CronFormatStandard, CronFormatWithSeconds, CronFormatDescriptor:
+		return true
+	}
+	return false
+}
+
+func (e CronFormat) String() string {
+	return string(e)
+}
+
+func (e *CronFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CronFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CronFormat", str)
+	}
+	return nil
+}
+
+func (e CronFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DependencyCondition string
+
+const (
+	DependencyConditionCompleted   DependencyCondition = "COMPLETED"
+	DependencyConditionAnyTerminal DependencyCondition = "ANY_TERMINAL"
+)
+
+var AllDependencyCondition = []DependencyCondition{
+	DependencyConditionCompleted,
+	DependencyConditionAnyTerminal,
+}
+
+func (e DependencyCondition) IsValid() bool {
+	switch e {
+	case DependencyConditionCompleted, DependencyConditionAnyTerminal:
+		return true
+	}
+	return false
+}
+
+func (e DependencyCondition) String() string {
+	return string(e)
+}
+
+func (e *DependencyCondition) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DependencyCondition(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DependencyCondition", str)
+	}
+	return nil
+}
+
+func (e DependencyCondition) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type FailureAction string
+
+const (
+	FailureActionNone     FailureAction = "NONE"
+	FailureActionRetry    FailureAction = "RETRY"
+	FailureActionRestart  FailureAction = "RESTART"
+	FailureActionRelaunch FailureAction = "RELAUNCH"
+)
+
+var AllFailureAction = []FailureAction{
+	FailureActionNone,
+	FailureActionRetry,
+	FailureActionRestart,
+	FailureActionRelaunch,
+}
+
+func (e FailureAction) IsValid() bool {
+	switch e {
+	case FailureActionNone, FailureActionRetry, FailureActionRestart, FailureActionRelaunch:
+		return true
+	}
+	return false
+}
+
+func (e FailureAction) String() string {
+	return string(e)
+}
+
+func (e *FailureAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FailureAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FailureAction", str)
+	}
+	return nil
+}
+
+func (e FailureAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type JitterMode string
+
+const (
+	JitterModeRandom     JitterMode = "RANDOM"
+	JitterModeHashOffset JitterMode = "HASH_OFFSET"
+)
+
+var AllJitterMode = []JitterMode{
+	JitterModeRandom,
+	JitterModeHashOffset,
+}
+
+func (e JitterMode) IsValid() bool {
+	switch e {
+	case JitterModeRandom, JitterModeHashOffset:
+		return true
+	}
+	return false
+}
+
+func (e JitterMode) String() string {
+	return string(e)
+}
+
+func (e *JitterMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JitterMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JitterMode", str)
+	}
+	return nil
+}
+
+func (e JitterMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MisfirePolicy string
+
+const (
+	MisfirePolicySkip     MisfirePolicy = "SKIP"
+	MisfirePolicyFireOnce MisfirePolicy = "FIRE_ONCE"
+	MisfirePolicyFireAll  MisfirePolicy = "FIRE_ALL"
+)
+
+var AllMisfirePolicy = []MisfirePolicy{
+	MisfirePolicySkip,
+	MisfirePolicyFireOnce,
+	MisfirePolicyFireAll,
+}
+
+func (e MisfirePolicy) IsValid() bool {
+	switch e {
+	case MisfirePolicySkip, MisfirePolicyFireOnce, MisfirePolicyFireAll:
+		return true
+	}
+	return false
+}
+
+func (e MisfirePolicy) String() string {
+	return string(e)
+}
+
+func (e *MisfirePolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MisfirePolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MisfirePolicy", str)
+	}
+	return nil
+}
+
+func (e MisfirePolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OverrunAction string
+
+const (
+	OverrunActionNone                 OverrunAction = "NONE"
+	OverrunActionTerminate            OverrunAction = "TERMINATE"
+	OverrunActionTerminateAndRelaunch OverrunAction = "TERMINATE_AND_RELAUNCH"
+)
+
+var AllOverrunAction = []OverrunAction{
+	OverrunActionNone,
+	OverrunActionTerminate,
+	OverrunActionTerminateAndRelaunch,
+}
+
+func (e OverrunAction) IsValid() bool {
+	switch e {
+	case OverrunActionNone, OverrunActionTerminate, OverrunActionTerminateAndRelaunch:
+		return true
+	}
+	return false
+}
+
+func (e OverrunAction) String() string {
+	return string(e)
+}
+
+func (e *OverrunAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OverrunAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OverrunAction", str)
+	}
+	return nil
+}
+
+func (e OverrunAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ScheduleCondition string
+
+const (
+	ScheduleConditionOk      ScheduleCondition = "OK"
+	ScheduleConditionWarning ScheduleCondition = "WARNING"
+)
+
+var AllScheduleCondition = []ScheduleCondition{
+	ScheduleConditionOk,
+	ScheduleConditionWarning,
+}
+
+func (e ScheduleCondition) IsValid() bool {
+	switch e {
+	case ScheduleConditionOk, ScheduleConditionWarning:
+		return true
+	}
+	return false
+}
+
+func (e ScheduleCondition) String() string {
+	return string(e)
+}
+
+func (e *ScheduleCondition) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduleCondition(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduleCondition", str)
+	}
+	return nil
+}
+
+func (e ScheduleCondition) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ScheduleSortField string
+
+const (
+	ScheduleSortFieldName       ScheduleSortField = "NAME"
+	ScheduleSortFieldLastUpdate ScheduleSortField = "LAST_UPDATE"
+	ScheduleSortFieldNextRun    ScheduleSortField = "NEXT_RUN"
+	ScheduleSortFieldLastRun    ScheduleSortField = "LAST_RUN"
+	ScheduleSortFieldStatus     ScheduleSortField = "STATUS"
+)
+
+var AllScheduleSortField = []ScheduleSortField{
+	ScheduleSortFieldName,
+	ScheduleSortFieldLastUpdate,
+	ScheduleSortFieldNextRun,
+	ScheduleSortFieldLastRun,
+	ScheduleSortFieldStatus,
+}
+
+func (e ScheduleSortField) IsValid() bool {
+	switch e {
+	case ScheduleSortFieldName, ScheduleSortFieldLastUpdate, ScheduleSortFieldNextRun, ScheduleSortFieldLastRun, ScheduleSortFieldStatus:
+		return true
+	}
+	return false
+}
+
+func (e ScheduleSortField) String() string {
+	return string(e)
+}
+
+func (e *ScheduleSortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduleSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduleSortField", str)
+	}
+	return nil
+}
+
+func (e ScheduleSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
 type Status string
@@ -83,6 +771,10 @@ const (
 	StatusRunning    Status = "RUNNING"
 	StatusTerminated Status = "TERMINATED"
 	StatusTimedOut   Status = "TIMED_OUT"
+	StatusQueued     Status = "QUEUED"
+	StatusSkipped    Status = "SKIPPED"
+	StatusPending    Status = "PENDING"
+	StatusRetrying   Status = "RETRYING"
 )
 
 var AllStatus = []Status{
@@ -93,11 +785,15 @@ var AllStatus = []Status{
 	StatusRunning,
 	StatusTerminated,
 	StatusTimedOut,
+	StatusQueued,
+	StatusSkipped,
+	StatusPending,
+	StatusRetrying,
 }
 
 func (e Status) IsValid() bool {
 	switch e {
-	case StatusUnknown, StatusCompleted, StatusFailed, StatusPaused, StatusRunning, StatusTerminated, StatusTimedOut:
+	case StatusUnknown, StatusCompleted, StatusFailed, StatusPaused, StatusRunning, StatusTerminated, StatusTimedOut, StatusQueued, StatusSkipped, StatusPending, StatusRetrying:
 		return true
 	}
 	return false
@@ -123,3 +819,142 @@ func (e *Status) UnmarshalGQL(v interface{}) error {
 func (e Status) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type TriggerMode string
+
+const (
+	TriggerModeCron     TriggerMode = "CRON"
+	TriggerModeUpstream TriggerMode = "UPSTREAM"
+)
+
+var AllTriggerMode = []TriggerMode{
+	TriggerModeCron,
+	TriggerModeUpstream,
+}
+
+func (e TriggerMode) IsValid() bool {
+	switch e {
+	case TriggerModeCron, TriggerModeUpstream:
+		return true
+	}
+	return false
+}
+
+func (e TriggerMode) String() string {
+	return string(e)
+}
+
+func (e *TriggerMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TriggerMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TriggerMode", str)
+	}
+	return nil
+}
+
+func (e TriggerMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TriggerSource string
+
+const (
+	TriggerSourceTimer    TriggerSource = "TIMER"
+	TriggerSourceManual   TriggerSource = "MANUAL"
+	TriggerSourceMisfire  TriggerSource = "MISFIRE"
+	TriggerSourceRelaunch TriggerSource = "RELAUNCH"
+	TriggerSourceUpstream TriggerSource = "UPSTREAM"
+)
+
+var AllTriggerSource = []TriggerSource{
+	TriggerSourceTimer,
+	TriggerSourceManual,
+	TriggerSourceMisfire,
+	TriggerSourceRelaunch,
+	TriggerSourceUpstream,
+}
+
+func (e TriggerSource) IsValid() bool {
+	switch e {
+	case TriggerSourceTimer, TriggerSourceManual, TriggerSourceMisfire, TriggerSourceRelaunch, TriggerSourceUpstream:
+		return true
+	}
+	return false
+}
+
+func (e TriggerSource) String() string {
+	return string(e)
+}
+
+func (e *TriggerSource) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TriggerSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TriggerSource", str)
+	}
+	return nil
+}
+
+func (e TriggerSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Weekday string
+
+const (
+	WeekdayMonday    Weekday = "MONDAY"
+	WeekdayTuesday   Weekday = "TUESDAY"
+	WeekdayWednesday Weekday = "WEDNESDAY"
+	WeekdayThursday  Weekday = "THURSDAY"
+	WeekdayFriday    Weekday = "FRIDAY"
+	WeekdaySaturday  Weekday = "SATURDAY"
+	WeekdaySunday    Weekday = "SUNDAY"
+)
+
+var AllWeekday = []Weekday{
+	WeekdayMonday,
+	WeekdayTuesday,
+	WeekdayWednesday,
+	WeekdayThursday,
+	WeekdayFriday,
+	WeekdaySaturday,
+	WeekdaySunday,
+}
+
+func (e Weekday) IsValid() bool {
+	switch e {
+	case WeekdayMonday, WeekdayTuesday, WeekdayWednesday, WeekdayThursday, WeekdayFriday, WeekdaySaturday, WeekdaySunday:
+		return true
+	}
+	return false
+}
+
+func (e Weekday) String() string {
+	return string(e)
+}
+
+func (e *Weekday) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Weekday(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Weekday", str)
+	}
+	return nil
+}
+
+func (e Weekday) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
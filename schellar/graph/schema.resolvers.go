@@ -6,8 +6,9 @@ package graph
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/frinx/schellar/graph/model"
@@ -16,11 +17,29 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Contains is the resolver for the contains field.
+func (r *calendarResolver) Contains(ctx context.Context, obj *model.Calendar, time string) (bool, error) {
+	t, err := ConvertDateTime(time)
+	if err != nil {
+		return false, fmt.Errorf("Error while parsing the date time. err=%v", err)
+	}
+
+	calendar, err := scheduler.Configuration.Db.FindCalendarByName(obj.Name)
+	if err != nil {
+		logrus.Debugf("Error getting calendar with name '%s'. err=%v", obj.Name, err)
+		return false, fmt.Errorf("Error getting calendar with name '%s'. err=%v", obj.Name, err)
+	}
+	if calendar == nil {
+		return false, fmt.Errorf("Calendar not found with name '%s'", obj.Name)
+	}
+	return calendar.Match(t) != "", nil
+}
+
 // CreateSchedule is the resolver for the createSchedule field.
 func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.CreateScheduleInput) (*model.Schedule, error) {
 	err := checkPermissions(ctx)
 	if err != nil {
-		fmt.Println(err)
+		logrus.Warnf("Error checking permissions. err=%v", err)
 		return nil, fmt.Errorf("%v", err)
 	}
 
@@ -28,7 +47,26 @@ func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.Creat
 		Name:            input.Name,
 		WorkflowName:    input.WorkflowName,
 		WorkflowVersion: input.WorkflowVersion,
-		CronString:      input.CronString,
+	}
+
+	if input.CronString != nil {
+		schedule.CronString = *input.CronString
+	}
+
+	if input.CronFormat != nil {
+		schedule.CronFormat = input.CronFormat.String()
+	}
+
+	if input.TimeZone != nil {
+		schedule.TimeZone = *input.TimeZone
+	}
+
+	if input.JitterSeconds != nil {
+		schedule.JitterSeconds = *input.JitterSeconds
+	}
+
+	if input.JitterMode != nil {
+		schedule.JitterMode = input.JitterMode.String()
 	}
 
 	if input.Enabled != nil {
@@ -37,18 +75,113 @@ func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.Creat
 
 	if input.ParallelRuns != nil {
 		schedule.ParallelRuns = *input.ParallelRuns
+		// derived again from parallelRuns unless set explicitly
+		schedule.ConcurrencyPolicy = ""
+	}
+
+	if input.ConcurrencyPolicy != nil {
+		schedule.ConcurrencyPolicy = input.ConcurrencyPolicy.String()
+	}
+
+	if input.MaxConcurrentRuns != nil {
+		schedule.MaxConcurrentRuns = *input.MaxConcurrentRuns
+	}
+
+	if input.TriggerMode != nil {
+		schedule.TriggerMode = input.TriggerMode.String()
+	}
+
+	if input.DependsOn != nil {
+		schedule.DependsOn = ConvertDependencyInputs(input.DependsOn)
+	}
+
+	if input.IncludeCalendars != nil {
+		schedule.IncludeCalendars = input.IncludeCalendars
+	}
+
+	if input.ExcludeCalendars != nil {
+		schedule.ExcludeCalendars = input.ExcludeCalendars
+	}
+
+	if input.MisfirePolicy != nil {
+		schedule.MisfirePolicy = input.MisfirePolicy.String()
+	}
+
+	if input.MisfireMaxCount != nil {
+		schedule.MisfireMaxCount = *input.MisfireMaxCount
 	}
 
 	if input.WorkflowContext != nil {
-		var workflowContext map[string]interface{}
-		json.Unmarshal([]byte(*input.WorkflowContext), &workflowContext)
-		schedule.WorkflowContext = workflowContext
+		schedule.WorkflowContext = input.WorkflowContext
+	}
+
+	if input.OutputMapping != nil {
+		outputMapping, err := ConvertOutputMapping(input.OutputMapping)
+		if err != nil {
+			logrus.Debugf("Error validating schedule. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+		schedule.OutputMapping = outputMapping
+	}
+
+	if input.UpdateContextOnFailure != nil {
+		schedule.UpdateContextOnFailure = *input.UpdateContextOnFailure
+	}
+
+	if input.MaxContextBytes != nil {
+		schedule.MaxContextBytes = *input.MaxContextBytes
+	}
+
+	if input.CorrelationID != nil {
+		schedule.CorrelationID = *input.CorrelationID
+	}
+
+	if input.TaskToDomain != nil {
+		taskToDomain, err := ConvertTaskToDomain(input.TaskToDomain)
+		if err != nil {
+			logrus.Debugf("Error validating schedule. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+		schedule.TaskToDomain = taskToDomain
+	}
+
+	if input.Labels != nil {
+		labels, err := ConvertLabels(input.Labels)
+		if err != nil {
+			logrus.Debugf("Error validating schedule. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+		schedule.Labels = labels
+	}
+
+	if input.CheckWarningSeconds != nil {
+		schedule.CheckWarningSeconds = *input.CheckWarningSeconds
+	}
+
+	if input.MaxRunDuration != nil {
+		schedule.MaxRunDuration = *input.MaxRunDuration
+	}
+
+	if input.OnOverrun != nil {
+		schedule.OnOverrun = input.OnOverrun.String()
+	}
+
+	if input.RetryPolicy != nil {
+		schedule.RetryPolicy = ConvertRetryPolicyInput(input.RetryPolicy)
+	}
+
+	if input.OnFailure != nil {
+		schedule.OnFailure = input.OnFailure.String()
+	}
+
+	if input.OnFailureMaxCount != nil {
+		schedule.OnFailureMaxCount = *input.OnFailureMaxCount
 	}
 
 	if input.FromDate != nil {
 		fromDate, err := time.Parse(time.RFC3339, *input.FromDate)
 		if err != nil {
-			fmt.Println("Error while parsing the date time :", err)
+			logrus.Debugf("Error while parsing the date time. err=%v", err)
 			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
 
 		}
@@ -58,7 +191,7 @@ func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.Creat
 	if input.ToDate != nil {
 		toDate, err := time.Parse(time.RFC3339, *input.ToDate)
 		if err != nil {
-			fmt.Println("Error while parsing the date time :", err)
+			logrus.Debugf("Error while parsing the date time. err=%v", err)
 			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
 
 		}
@@ -71,6 +204,26 @@ func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.Creat
 		return nil, fmt.Errorf("Error validating schedule %s", err)
 	}
 
+	err = ValidateDependencies(&schedule)
+	if err != nil {
+		logrus.Debugf("Error validating schedule dependencies. err=%v", err)
+		return nil, fmt.Errorf("Error validating schedule %s", err)
+	}
+
+	err = ValidateCalendars(&schedule)
+	if err != nil {
+		logrus.Debugf("Error validating schedule calendars. err=%v", err)
+		return nil, fmt.Errorf("Error validating schedule %s", err)
+	}
+
+	if shouldValidateWorkflow(input.SkipWorkflowValidation) {
+		err = scheduler.ValidateWorkflow(&schedule)
+		if err != nil {
+			logrus.Debugf("Error validating schedule workflow. err=%v", err)
+			return nil, ConvertValidationError(ctx, err)
+		}
+	}
+
 	found, err := scheduler.Configuration.Db.FindByName(schedule.Name)
 	if err != nil {
 		logrus.Debugf("Error checking for existing schedule name. err=%v", err)
@@ -90,6 +243,7 @@ func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.Creat
 
 	}
 	scheduler.PrepareTimers()
+	scheduler.RefreshNextFireTime(schedule.Name)
 
 	return ConvertIfcToModel(&schedule), nil
 }
@@ -98,7 +252,7 @@ func (r *mutationResolver) CreateSchedule(ctx context.Context, input model.Creat
 func (r *mutationResolver) UpdateSchedule(ctx context.Context, name string, input model.UpdateScheduleInput) (*model.Schedule, error) {
 	err := checkPermissions(ctx)
 	if err != nil {
-		fmt.Println(err)
+		logrus.Warnf("Error checking permissions. err=%v", err)
 		return nil, fmt.Errorf("%s", err)
 	}
 
@@ -131,24 +285,136 @@ func (r *mutationResolver) UpdateSchedule(ctx context.Context, name string, inpu
 		schedule.CronString = *input.CronString
 	}
 
+	if input.CronFormat != nil {
+		schedule.CronFormat = input.CronFormat.String()
+	}
+
+	if input.TimeZone != nil {
+		schedule.TimeZone = *input.TimeZone
+	}
+
+	if input.JitterSeconds != nil {
+		schedule.JitterSeconds = *input.JitterSeconds
+	}
+
+	if input.JitterMode != nil {
+		schedule.JitterMode = input.JitterMode.String()
+	}
+
 	if input.Enabled != nil {
-		schedule.Enabled = *input.Enabled
+		schedule.SetEnabled(*input.Enabled, time.Now())
 	}
 
 	if input.ParallelRuns != nil {
 		schedule.ParallelRuns = *input.ParallelRuns
+		// derived again from parallelRuns unless set explicitly
+		schedule.ConcurrencyPolicy = ""
+	}
+
+	if input.ConcurrencyPolicy != nil {
+		schedule.ConcurrencyPolicy = input.ConcurrencyPolicy.String()
+	}
+
+	if input.MaxConcurrentRuns != nil {
+		schedule.MaxConcurrentRuns = *input.MaxConcurrentRuns
+	}
+
+	if input.TriggerMode != nil {
+		schedule.TriggerMode = input.TriggerMode.String()
+	}
+
+	if input.DependsOn != nil {
+		schedule.DependsOn = ConvertDependencyInputs(input.DependsOn)
+	}
+
+	if input.IncludeCalendars != nil {
+		schedule.IncludeCalendars = input.IncludeCalendars
+	}
+
+	if input.ExcludeCalendars != nil {
+		schedule.ExcludeCalendars = input.ExcludeCalendars
+	}
+
+	if input.MisfirePolicy != nil {
+		schedule.MisfirePolicy = input.MisfirePolicy.String()
+	}
+
+	if input.MisfireMaxCount != nil {
+		schedule.MisfireMaxCount = *input.MisfireMaxCount
 	}
 
+	previousContext := schedule.WorkflowContext
 	if input.WorkflowContext != nil {
-		var workflowContext map[string]interface{}
-		json.Unmarshal([]byte(*input.WorkflowContext), &workflowContext)
-		schedule.WorkflowContext = workflowContext
+		schedule.WorkflowContext = input.WorkflowContext
+	}
+
+	if input.OutputMapping != nil {
+		outputMapping, err := ConvertOutputMapping(input.OutputMapping)
+		if err != nil {
+			logrus.Debugf("Error validating schedule. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+		schedule.OutputMapping = outputMapping
+	}
+
+	if input.UpdateContextOnFailure != nil {
+		schedule.UpdateContextOnFailure = *input.UpdateContextOnFailure
+	}
+
+	if input.MaxContextBytes != nil {
+		schedule.MaxContextBytes = *input.MaxContextBytes
+	}
+
+	if input.CorrelationID != nil {
+		schedule.CorrelationID = *input.CorrelationID
+	}
+
+	if input.TaskToDomain != nil {
+		taskToDomain, err := ConvertTaskToDomain(input.TaskToDomain)
+		if err != nil {
+			logrus.Debugf("Error validating schedule. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+		schedule.TaskToDomain = taskToDomain
+	}
+
+	if input.Labels != nil {
+		labels, err := ConvertLabels(input.Labels)
+		if err != nil {
+			logrus.Debugf("Error validating schedule. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+		schedule.Labels = labels
+	}
+
+	if input.CheckWarningSeconds != nil {
+		schedule.CheckWarningSeconds = *input.CheckWarningSeconds
+	}
+
+	if input.MaxRunDuration != nil {
+		schedule.MaxRunDuration = *input.MaxRunDuration
+	}
+
+	if input.OnOverrun != nil {
+		schedule.OnOverrun = input.OnOverrun.String()
+	}
+
+	if input.RetryPolicy != nil {
+		schedule.RetryPolicy = ConvertRetryPolicyInput(input.RetryPolicy)
+	}
+
+	if input.OnFailure != nil {
+		schedule.OnFailure = input.OnFailure.String()
+	}
+
+	if input.OnFailureMaxCount != nil {
+		schedule.OnFailureMaxCount = *input.OnFailureMaxCount
 	}
 
 	if input.FromDate != nil {
 		fromDate, err := time.Parse(time.RFC3339, *input.FromDate)
 		if err != nil {
-			fmt.Println("Error while parsing the date time :", err)
+			logrus.Debugf("Error while parsing the date time. err=%v", err)
 			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
 
 		}
@@ -158,7 +424,7 @@ func (r *mutationResolver) UpdateSchedule(ctx context.Context, name string, inpu
 	if input.ToDate != nil {
 		toDate, err := time.Parse(time.RFC3339, *input.ToDate)
 		if err != nil {
-			fmt.Println("Error while parsing the date time :", err)
+			logrus.Debugf("Error while parsing the date time. err=%v", err)
 			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
 
 		}
@@ -171,6 +437,41 @@ func (r *mutationResolver) UpdateSchedule(ctx context.Context, name string, inpu
 		return nil, fmt.Errorf("Error validating schedule %s", err)
 	}
 
+	if input.DependsOn != nil {
+		err = ValidateDependencies(schedule)
+		if err != nil {
+			logrus.Debugf("Error validating schedule dependencies. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+	}
+
+	if input.IncludeCalendars != nil || input.ExcludeCalendars != nil {
+		err = ValidateCalendars(schedule)
+		if err != nil {
+			logrus.Debugf("Error validating schedule calendars. err=%v", err)
+			return nil, fmt.Errorf("Error validating schedule %s", err)
+		}
+	}
+
+	workflowChanged := input.WorkflowName != nil || input.WorkflowVersion != nil || input.WorkflowContext != nil
+	if workflowChanged && shouldValidateWorkflow(input.SkipWorkflowValidation) {
+		err = scheduler.ValidateWorkflow(schedule)
+		if err != nil {
+			logrus.Debugf("Error validating schedule workflow. err=%v", err)
+			return nil, ConvertValidationError(ctx, err)
+		}
+	}
+
+	if input.WorkflowContext != nil && !reflect.DeepEqual(previousContext, schedule.WorkflowContext) {
+		replaced := *schedule
+		replaced.WorkflowContext = previousContext
+		err = scheduler.SaveContextVersion(replaced, ifc.ContextSourceUpdate, "")
+		if err != nil {
+			logrus.Debugf("Error storing workflow context version to the database. err=%s", err)
+			return nil, fmt.Errorf("Error storing workflow context version to the database. err=%s", err)
+		}
+	}
+
 	err = scheduler.Configuration.Db.Update(*schedule)
 	if err != nil {
 		logrus.Debugf("Error storing schedule to the database. err=%s", err)
@@ -179,6 +480,7 @@ func (r *mutationResolver) UpdateSchedule(ctx context.Context, name string, inpu
 	}
 
 	scheduler.PrepareTimers()
+	scheduler.RefreshNextFireTime(schedule.Name)
 	return ConvertIfcToModel(schedule), nil
 }
 
@@ -186,7 +488,7 @@ func (r *mutationResolver) UpdateSchedule(ctx context.Context, name string, inpu
 func (r *mutationResolver) DeleteSchedule(ctx context.Context, name string) (bool, error) {
 	err := checkPermissions(ctx)
 	if err != nil {
-		fmt.Println(err)
+		logrus.Warnf("Error checking permissions. err=%v", err)
 		return false, fmt.Errorf("%s", err)
 	}
 
@@ -211,11 +513,400 @@ func (r *mutationResolver) DeleteSchedule(ctx context.Context, name string) (boo
 	return true, nil
 }
 
+// TriggerSchedule is the resolver for the triggerSchedule field.
+func (r *mutationResolver) TriggerSchedule(ctx context.Context, name string, inputOverride map[string]interface{}, ignoreParallelRuns *bool) (string, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return "", fmt.Errorf("%s", err)
+	}
+
+	err = ValidateName(name)
+	if err != nil {
+		logrus.Debugf("Error validating schedule. err=%v", err)
+		return "", fmt.Errorf("Error validating schedule %s", err)
+	}
+
+	schedule, err := scheduler.Configuration.Db.FindByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting schedule with name '%s'. err=%v", name, err)
+		return "", fmt.Errorf("Error getting schedule with name '%s'. err=%v", name, err)
+	}
+	if schedule == nil {
+		logrus.Debugf("Schedule not found with name '%s'", name)
+		return "", fmt.Errorf("Schedule not found with name '%s'", name)
+	}
+
+	workflowID, err := scheduler.FireSchedule(name, time.Now(), scheduler.TriggerManual,
+		inputOverride, ignoreParallelRuns != nil && *ignoreParallelRuns)
+	if err != nil {
+		logrus.Debugf("Error triggering schedule '%s'. err=%v", name, err)
+		return "", fmt.Errorf("Error triggering schedule '%s'. err=%v", name, err)
+	}
+	return workflowID, nil
+}
+
+// RestoreWorkflowContext is the resolver for the restoreWorkflowContext field.
+func (r *mutationResolver) RestoreWorkflowContext(ctx context.Context, name string, version int) (*model.Schedule, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%s", err)
+	}
+
+	err = ValidateName(name)
+	if err != nil {
+		logrus.Debugf("Error validating schedule. err=%v", err)
+		return nil, fmt.Errorf("Error validating schedule %s", err)
+	}
+
+	schedule, err := scheduler.Configuration.Db.FindByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting schedule with name '%s'. err=%v", name, err)
+		return nil, fmt.Errorf("Error getting schedule with name '%s'. err=%v", name, err)
+	}
+	if schedule == nil {
+		logrus.Debugf("Schedule not found with name '%s'", name)
+		return nil, fmt.Errorf("Schedule not found with name '%s'", name)
+	}
+
+	contextVersion, err := scheduler.Configuration.Db.FindContextVersion(name, version)
+	if err != nil {
+		logrus.Debugf("Error getting workflow context version %d of schedule '%s'. err=%v", version, name, err)
+		return nil, fmt.Errorf("Error getting workflow context version %d of schedule '%s'. err=%v", version, name, err)
+	}
+	if contextVersion == nil {
+		logrus.Debugf("Workflow context version %d not found for schedule '%s'", version, name)
+		return nil, fmt.Errorf("Workflow context version %d not found for schedule '%s'", version, name)
+	}
+
+	// the current context becomes a new version, so the restore itself can be undone
+	err = scheduler.SaveContextVersion(*schedule, ifc.ContextSourceRestore, "")
+	if err != nil {
+		logrus.Debugf("Error storing workflow context version to the database. err=%s", err)
+		return nil, fmt.Errorf("Error storing workflow context version to the database. err=%s", err)
+	}
+
+	schedule.WorkflowContext = contextVersion.WorkflowContext
+	err = scheduler.Configuration.Db.UpdateStatusAndWorkflowContext(*schedule)
+	if err != nil {
+		logrus.Debugf("Error storing schedule to the database. err=%s", err)
+		return nil, fmt.Errorf("Error storing schedule to the database. err=%s", err)
+	}
+	return ConvertIfcToModel(schedule), nil
+}
+
+// PauseSchedule is the resolver for the pauseSchedule field.
+func (r *mutationResolver) PauseSchedule(ctx context.Context, name string, reason *string, until *string) (*model.Schedule, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%s", err)
+	}
+
+	err = ValidateName(name)
+	if err != nil {
+		logrus.Debugf("Error validating schedule. err=%v", err)
+		return nil, fmt.Errorf("Error validating schedule %s", err)
+	}
+
+	schedule, err := scheduler.Configuration.Db.FindByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting schedule with name '%s'. err=%v", name, err)
+		return nil, fmt.Errorf("Error getting schedule with name '%s'. err=%v", name, err)
+	}
+	if schedule == nil {
+		logrus.Debugf("Schedule not found with name '%s'", name)
+		return nil, fmt.Errorf("Schedule not found with name '%s'", name)
+	}
+
+	err = PauseSchedule(schedule, getUserHeader(ctx), reason, until)
+	if err != nil {
+		logrus.Debugf("Error pausing schedule '%s'. err=%v", name, err)
+		return nil, fmt.Errorf("Error pausing schedule '%s'. err=%v", name, err)
+	}
+	logrus.Infof("Schedule %s: Paused by %s. reason=%s", name, schedule.PausedBy, schedule.PauseReason)
+
+	scheduler.PrepareTimers()
+	scheduler.RefreshNextFireTime(name)
+	return ConvertIfcToModel(schedule), nil
+}
+
+// ResumeSchedule is the resolver for the resumeSchedule field.
+func (r *mutationResolver) ResumeSchedule(ctx context.Context, name string) (*model.Schedule, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%s", err)
+	}
+
+	err = ValidateName(name)
+	if err != nil {
+		logrus.Debugf("Error validating schedule. err=%v", err)
+		return nil, fmt.Errorf("Error validating schedule %s", err)
+	}
+
+	schedule, err := scheduler.Configuration.Db.FindByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting schedule with name '%s'. err=%v", name, err)
+		return nil, fmt.Errorf("Error getting schedule with name '%s'. err=%v", name, err)
+	}
+	if schedule == nil {
+		logrus.Debugf("Schedule not found with name '%s'", name)
+		return nil, fmt.Errorf("Schedule not found with name '%s'", name)
+	}
+	if !schedule.IsPaused() {
+		logrus.Debugf("Schedule '%s' is not paused", name)
+		return nil, fmt.Errorf("Schedule '%s' is not paused", name)
+	}
+
+	err = ResumeSchedule(schedule)
+	if err != nil {
+		logrus.Debugf("Error storing schedule to the database. err=%s", err)
+		return nil, fmt.Errorf("Error storing schedule to the database. err=%s", err)
+	}
+	logrus.Infof("Schedule %s: Resumed by %s", name, getUserHeader(ctx))
+
+	scheduler.PrepareTimers()
+	scheduler.RefreshNextFireTime(name)
+	return ConvertIfcToModel(schedule), nil
+}
+
+// PauseSchedules is the resolver for the pauseSchedules field.
+func (r *mutationResolver) PauseSchedules(ctx context.Context, selector model.ScheduleSelectorInput, reason *string, until *string) ([]*model.Schedule, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%s", err)
+	}
+
+	schedules, err := FindSchedulesBySelector(selector)
+	if err != nil {
+		logrus.Debugf("Error finding schedules. err=%v", err)
+		return nil, fmt.Errorf("Error finding schedules. err=%v", err)
+	}
+
+	// schedules disabled without a pause stay disabled
+	paused := make([]*model.Schedule, 0)
+	for i := range schedules {
+		schedule := &schedules[i]
+		if !schedule.Enabled && !schedule.IsPaused() {
+			continue
+		}
+		err = PauseSchedule(schedule, getUserHeader(ctx), reason, until)
+		if err != nil {
+			logrus.Debugf("Error pausing schedule '%s'. err=%v", schedule.Name, err)
+			scheduler.PrepareTimers()
+			return nil, fmt.Errorf("Error pausing schedule '%s'. err=%v", schedule.Name, err)
+		}
+		logrus.Infof("Schedule %s: Paused by %s. reason=%s", schedule.Name, schedule.PausedBy, schedule.PauseReason)
+		scheduler.RefreshNextFireTime(schedule.Name)
+		paused = append(paused, ConvertIfcToModel(schedule))
+	}
+
+	scheduler.PrepareTimers()
+	return paused, nil
+}
+
+// ResumeSchedules is the resolver for the resumeSchedules field.
+func (r *mutationResolver) ResumeSchedules(ctx context.Context, selector model.ScheduleSelectorInput) ([]*model.Schedule, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%s", err)
+	}
+
+	schedules, err := FindSchedulesBySelector(selector)
+	if err != nil {
+		logrus.Debugf("Error finding schedules. err=%v", err)
+		return nil, fmt.Errorf("Error finding schedules. err=%v", err)
+	}
+
+	resumed := make([]*model.Schedule, 0)
+	for i := range schedules {
+		schedule := &schedules[i]
+		if !schedule.IsPaused() {
+			continue
+		}
+		err = ResumeSchedule(schedule)
+		if err != nil {
+			logrus.Debugf("Error storing schedule to the database. err=%s", err)
+			scheduler.PrepareTimers()
+			return nil, fmt.Errorf("Error storing schedule to the database. err=%s", err)
+		}
+		logrus.Infof("Schedule %s: Resumed by %s", schedule.Name, getUserHeader(ctx))
+		scheduler.RefreshNextFireTime(schedule.Name)
+		resumed = append(resumed, ConvertIfcToModel(schedule))
+	}
+
+	scheduler.PrepareTimers()
+	return resumed, nil
+}
+
+// CreateCalendar is the resolver for the createCalendar field.
+func (r *mutationResolver) CreateCalendar(ctx context.Context, input model.CreateCalendarInput) (*model.Calendar, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	calendar := ifc.Calendar{Name: input.Name}
+
+	if input.Description != nil {
+		calendar.Description = *input.Description
+	}
+
+	if input.TimeZone != nil {
+		calendar.TimeZone = *input.TimeZone
+	}
+
+	if input.DateRanges != nil {
+		calendar.DateRanges, err = ConvertDateRangeInputs(input.DateRanges)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if input.WeeklyWindows != nil {
+		calendar.WeeklyWindows = ConvertWeeklyWindowInputs(input.WeeklyWindows)
+	}
+
+	if input.Ical != nil {
+		err = ImportICal(&calendar, *input.Ical)
+		if err != nil {
+			logrus.Debugf("Error validating calendar. err=%v", err)
+			return nil, fmt.Errorf("Error validating calendar %s", err)
+		}
+	}
+
+	err = calendar.ValidateAndUpdate()
+	if err != nil {
+		logrus.Debugf("Error validating calendar. err=%v", err)
+		return nil, fmt.Errorf("Error validating calendar %s", err)
+	}
+
+	found, err := scheduler.Configuration.Db.FindCalendarByName(calendar.Name)
+	if err != nil {
+		logrus.Debugf("Error checking for existing calendar name. err=%v", err)
+		return nil, fmt.Errorf("Error checking for existing calendar name")
+	}
+	if found != nil {
+		logrus.Debugf("Duplicate calendar name '%s'", calendar.Name)
+		return nil, fmt.Errorf("Duplicate calendar name '%s'", calendar.Name)
+	}
+
+	err = scheduler.Configuration.Db.InsertCalendar(calendar)
+	if err != nil {
+		logrus.Debugf("Error storing calendar to the database. err=%s", err)
+		return nil, fmt.Errorf("Error storing calendar to the database. err=%s", err)
+	}
+	return ConvertCalendarToModel(&calendar), nil
+}
+
+// UpdateCalendar is the resolver for the updateCalendar field.
+func (r *mutationResolver) UpdateCalendar(ctx context.Context, name string, input model.UpdateCalendarInput) (*model.Calendar, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%s", err)
+	}
+
+	calendar, err := scheduler.Configuration.Db.FindCalendarByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting calendar with name '%s'. err=%v", name, err)
+		return nil, fmt.Errorf("Error getting calendar with name '%s'. err=%v", name, err)
+	}
+	if calendar == nil {
+		logrus.Debugf("Calendar not found with name '%s'", name)
+		return nil, fmt.Errorf("Calendar not found with name '%s'", name)
+	}
+
+	if input.Description != nil {
+		calendar.Description = *input.Description
+	}
+
+	if input.TimeZone != nil {
+		calendar.TimeZone = *input.TimeZone
+	}
+
+	// imported events replace date ranges together with the given ones
+	if input.DateRanges != nil || input.Ical != nil {
+		calendar.DateRanges, err = ConvertDateRangeInputs(input.DateRanges)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if input.WeeklyWindows != nil {
+		calendar.WeeklyWindows = ConvertWeeklyWindowInputs(input.WeeklyWindows)
+	}
+
+	if input.Ical != nil {
+		err = ImportICal(calendar, *input.Ical)
+		if err != nil {
+			logrus.Debugf("Error validating calendar. err=%v", err)
+			return nil, fmt.Errorf("Error validating calendar %s", err)
+		}
+	}
+
+	err = calendar.ValidateAndUpdate()
+	if err != nil {
+		logrus.Debugf("Error validating calendar. err=%v", err)
+		return nil, fmt.Errorf("Error validating calendar %s", err)
+	}
+
+	err = scheduler.Configuration.Db.UpdateCalendar(*calendar)
+	if err != nil {
+		logrus.Debugf("Error storing calendar to the database. err=%s", err)
+		return nil, fmt.Errorf("Error storing calendar to the database. err=%s", err)
+	}
+	return ConvertCalendarToModel(calendar), nil
+}
+
+// DeleteCalendar is the resolver for the deleteCalendar field.
+func (r *mutationResolver) DeleteCalendar(ctx context.Context, name string) (bool, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return false, fmt.Errorf("%s", err)
+	}
+
+	calendar, err := scheduler.Configuration.Db.FindCalendarByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting calendar with name '%s'. err=%v", name, err)
+		return false, fmt.Errorf("Error getting calendar with name '%s'. err=%v", name, err)
+	}
+	if calendar == nil {
+		logrus.Debugf("Calendar not found with name '%s'", name)
+		return false, fmt.Errorf("Calendar not found with name '%s'", name)
+	}
+
+	schedules, err := scheduler.Configuration.Db.FindAll()
+	if err != nil {
+		logrus.Debugf("Error getting schedules. err=%v", err)
+		return false, fmt.Errorf("Error getting schedules. err=%v", err)
+	}
+	for _, schedule := range schedules {
+		if schedule.UsesCalendar(name) {
+			logrus.Debugf("Calendar '%s' is used by schedule '%s'", name, schedule.Name)
+			return false, fmt.Errorf("Calendar '%s' is used by schedule '%s'", name, schedule.Name)
+		}
+	}
+
+	err = scheduler.Configuration.Db.RemoveCalendarByName(name)
+	if err != nil {
+		logrus.Debugf("Error deleting calendar. err=%v", err)
+		return false, fmt.Errorf("Error deleting calendar. err=%v", err)
+	}
+	return true, nil
+}
+
 // Schedule is the resolver for the schedule field.
 func (r *queryResolver) Schedule(ctx context.Context, name string) (*model.Schedule, error) {
 	err := checkPermissions(ctx)
 	if err != nil {
-		fmt.Println(err)
+		logrus.Warnf("Error checking permissions. err=%v", err)
 		return nil, fmt.Errorf("%v", err)
 	}
 
@@ -234,10 +925,10 @@ func (r *queryResolver) Schedule(ctx context.Context, name string) (*model.Sched
 }
 
 // Schedules is the resolver for the schedules field.
-func (r *queryResolver) Schedules(ctx context.Context, after *string, before *string, first *int, last *int, filter *model.SchedulesFilterInput) (*model.ScheduleConnection, error) {
+func (r *queryResolver) Schedules(ctx context.Context, after *string, before *string, first *int, last *int, filter *model.SchedulesFilterInput, orderBy *model.ScheduleOrderInput) (*model.ScheduleConnection, error) {
 	err := checkPermissions(ctx)
 	if err != nil {
-		fmt.Println(err)
+		logrus.Warnf("Error checking permissions. err=%v", err)
 		return nil, fmt.Errorf("%v", err)
 	}
 
@@ -254,12 +945,10 @@ func (r *queryResolver) Schedules(ctx context.Context, after *string, before *st
 		before = nil
 	}
 
-	var schedules []*model.Schedule
-
-	if filter != nil {
-		schedules = GetSchedulesFilter(filter)
-	} else {
-		schedules = GetSchedules()
+	schedules, err := GetSchedulesFilter(filter, orderBy)
+	if err != nil {
+		logrus.Debugf("Error finding schedules. err=%v", err)
+		return nil, fmt.Errorf("Error finding schedules. err=%v", err)
 	}
 
 	totalCount := len(schedules)
@@ -317,11 +1006,247 @@ func (r *queryResolver) Schedules(ctx context.Context, after *string, before *st
 	return &connections, nil
 }
 
+// PreviewCron is the resolver for the previewCron field.
+func (r *queryResolver) PreviewCron(ctx context.Context, cronString string, cronFormat *model.CronFormat, timeZone *string, count *int, from *string, jitterSeconds *int, jitterMode *model.JitterMode, name *string) ([]string, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	previewCount, err := getPreviewCount(count)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := ifc.Schedule{
+		Enabled:    true,
+		CronString: cronString,
+	}
+	if cronFormat != nil {
+		schedule.CronFormat = cronFormat.String()
+	}
+	if timeZone != nil {
+		schedule.TimeZone = *timeZone
+	}
+	if jitterSeconds != nil {
+		schedule.JitterSeconds = *jitterSeconds
+	}
+	if jitterMode != nil {
+		schedule.JitterMode = jitterMode.String()
+	}
+	if name != nil {
+		schedule.Name = *name
+	}
+	if schedule.JitterMode == ifc.JitterHashOffset && schedule.Name == "" {
+		return nil, errors.New("'name' is required with HASH_OFFSET 'jitterMode'")
+	}
+
+	fromTime := time.Now()
+	if from != nil {
+		fromTime, err = ConvertDateTime(*from)
+		if err != nil {
+			return nil, fmt.Errorf("Error while parsing the date time. err=%v", err)
+		}
+	}
+
+	fireTimes, err := schedule.NextFireTimes(fromTime, previewCount)
+	if err != nil {
+		logrus.Debugf("Error parsing cron string '%s'. err=%v", cronString, err)
+		return nil, fmt.Errorf("'cronString' is invalid. err=%v", err)
+	}
+	return FormatFireTimes(&schedule, fireTimes), nil
+}
+
+// Executions is the resolver for the executions field.
+func (r *queryResolver) Executions(ctx context.Context, scheduleName *string, status *model.Status, workflowName *string, after *string, first *int) (*model.ExecutionConnection, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	if first != nil && *first <= 0 {
+		return nil, errors.New("'first' has to be positive")
+	}
+
+	limit := defaultExecutionsPageSize
+	if first != nil {
+		limit = *first
+	}
+
+	cursor := ""
+	if after != nil {
+		cursor = *after
+	}
+
+	filter := ifc.ExecutionFilter{}
+	if scheduleName != nil {
+		filter.ScheduleName = *scheduleName
+	}
+	if status != nil {
+		filter.Status = status.String()
+	}
+	if workflowName != nil {
+		filter.WorkflowName = *workflowName
+	}
+
+	totalCount, err := scheduler.Configuration.Db.CountExecutions(filter)
+	if err != nil {
+		logrus.Debugf("Error counting executions. err=%v", err)
+		return nil, fmt.Errorf("Error counting executions. err=%v", err)
+	}
+
+	// load one more execution to find out if there is a next page
+	ifcExecutions, err := scheduler.Configuration.Db.FindExecutions(filter, cursor, limit+1)
+	if err != nil {
+		logrus.Debugf("Error getting executions. err=%v", err)
+		return nil, fmt.Errorf("Error getting executions. err=%v", err)
+	}
+
+	hasNext := len(ifcExecutions) > limit
+	if hasNext {
+		ifcExecutions = ifcExecutions[0:limit]
+	}
+
+	var startCursor string = ""
+	var endCursor string = ""
+	edges := make([]*model.ExecutionEdge, len(ifcExecutions))
+	for i, execution := range ifcExecutions {
+		edges[i] = &model.ExecutionEdge{
+			Cursor: execution.ID,
+			Node:   ConvertExecutionToModel(&execution),
+		}
+	}
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	pageInfo := model.PageInfo{
+		StartCursor:     &startCursor,
+		EndCursor:       &endCursor,
+		HasPreviousPage: cursor != "",
+		HasNextPage:     hasNext,
+	}
+
+	connections := model.ExecutionConnection{
+		Edges:      edges,
+		PageInfo:   &pageInfo,
+		TotalCount: totalCount,
+	}
+	return &connections, nil
+}
+
+// WorkflowContextVersions is the resolver for the workflowContextVersions field.
+func (r *queryResolver) WorkflowContextVersions(ctx context.Context, scheduleName string, first *int) ([]*model.ContextVersion, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	if first != nil && *first <= 0 {
+		return nil, errors.New("'first' has to be positive")
+	}
+
+	limit := 0
+	if first != nil {
+		limit = *first
+	}
+
+	versions, err := scheduler.Configuration.Db.FindContextVersions(scheduleName, limit)
+	if err != nil {
+		logrus.Debugf("Error getting workflow context versions of schedule '%s'. err=%v", scheduleName, err)
+		return nil, fmt.Errorf("Error getting workflow context versions of schedule '%s'. err=%v", scheduleName, err)
+	}
+
+	result := make([]*model.ContextVersion, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, ConvertContextVersionToModel(&version))
+	}
+	return result, nil
+}
+
+// Calendar is the resolver for the calendar field.
+func (r *queryResolver) Calendar(ctx context.Context, name string) (*model.Calendar, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	calendar, err := scheduler.Configuration.Db.FindCalendarByName(name)
+	if err != nil {
+		logrus.Debugf("Error getting calendar with name '%s'. err=%v", name, err)
+		return nil, fmt.Errorf("Error getting calendar with name '%s'. err=%v", name, err)
+	}
+	if calendar == nil {
+		return nil, nil
+	}
+	return ConvertCalendarToModel(calendar), nil
+}
+
+// Calendars is the resolver for the calendars field.
+func (r *queryResolver) Calendars(ctx context.Context) ([]*model.Calendar, error) {
+	err := checkPermissions(ctx)
+	if err != nil {
+		logrus.Warnf("Error checking permissions. err=%v", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	calendars, err := scheduler.Configuration.Db.FindAllCalendars()
+	if err != nil {
+		logrus.Debugf("Error getting calendars. err=%v", err)
+		return nil, fmt.Errorf("Error getting calendars. err=%v", err)
+	}
+
+	result := make([]*model.Calendar, 0, len(calendars))
+	for _, calendar := range calendars {
+		result = append(result, ConvertCalendarToModel(&calendar))
+	}
+	return result, nil
+}
+
+// NextRuns is the resolver for the nextRuns field.
+func (r *scheduleResolver) NextRuns(ctx context.Context, obj *model.Schedule, count *int) ([]string, error) {
+	previewCount, err := getPreviewCount(count)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := ConvertModelToCronSchedule(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars, err := scheduler.LoadCalendars(schedule)
+	if err != nil {
+		logrus.Debugf("Error loading calendars of schedule '%s'. err=%v", obj.Name, err)
+		return nil, fmt.Errorf("Error loading calendars of schedule '%s'. err=%v", obj.Name, err)
+	}
+
+	fireTimes, err := schedule.NextAllowedFireTimes(time.Now(), previewCount, calendars)
+	if err != nil {
+		logrus.Debugf("Error computing next runs of schedule '%s'. err=%v", obj.Name, err)
+		return nil, fmt.Errorf("Error computing next runs of schedule '%s'. err=%v", obj.Name, err)
+	}
+	return FormatFireTimes(schedule, fireTimes), nil
+}
+
+// Calendar returns CalendarResolver implementation.
+func (r *Resolver) Calendar() CalendarResolver { return &calendarResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Schedule returns ScheduleResolver implementation.
+func (r *Resolver) Schedule() ScheduleResolver { return &scheduleResolver{r} }
+
+type calendarResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type scheduleResolver struct{ *Resolver }
@@ -0,0 +1,215 @@
+package ifc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxCalendarCandidates limits fire times examined while looking for ones allowed by calendars
+const maxCalendarCandidates = 10000
+
+// Calendar is a named set of date ranges and weekly windows referenced by schedules
+// to forbid (excludeCalendars) or to restrict (includeCalendars) their fire times
+type Calendar struct {
+	Name        string `json:"name,omitempty" bson:"name"`
+	Description string `json:"description,omitempty" bson:"description"`
+	// TimeZone in which weekly windows and all day date ranges are evaluated, server time zone if empty
+	TimeZone      string         `json:"timeZone,omitempty" bson:"timeZone"`
+	DateRanges    []DateRange    `json:"dateRanges,omitempty" bson:"dateRanges"`
+	WeeklyWindows []WeeklyWindow `json:"weeklyWindows,omitempty" bson:"weeklyWindows"`
+	LastUpdate    time.Time      `json:"lastUpdate,omitempty" bson:"lastUpdate"`
+}
+
+// DateRange of a calendar, from is included and to is excluded
+type DateRange struct {
+	From        time.Time `json:"from" bson:"from"`
+	To          time.Time `json:"to" bson:"to"`
+	Description string    `json:"description,omitempty" bson:"description"`
+}
+
+// WeeklyWindow of a calendar repeating on the days of week between start and end time of day (HH:MM).
+// End time not after start time ends the window on the next day.
+type WeeklyWindow struct {
+	Days      []string `json:"days" bson:"days"`
+	StartTime string   `json:"startTime" bson:"startTime"`
+	EndTime   string   `json:"endTime" bson:"endTime"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"SUNDAY":    time.Sunday,
+	"MONDAY":    time.Monday,
+	"TUESDAY":   time.Tuesday,
+	"WEDNESDAY": time.Wednesday,
+	"THURSDAY":  time.Thursday,
+	"FRIDAY":    time.Friday,
+	"SATURDAY":  time.Saturday,
+}
+
+// Location returns time zone of the calendar, server time zone if not set
+func (calendar *Calendar) Location() (*time.Location, error) {
+	if calendar.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(calendar.TimeZone)
+}
+
+func (calendar *Calendar) ValidateAndUpdate() error {
+	if calendar.Name == "" {
+		return errors.New("'name' is required")
+	}
+	if strings.Contains(calendar.Name, "/") {
+		return errors.New("'name' cannot contain '/' character")
+	}
+	_, err := calendar.Location()
+	if err != nil {
+		return errors.Wrap(err, "'timeZone' is invalid")
+	}
+	for _, dateRange := range calendar.DateRanges {
+		if !dateRange.To.After(dateRange.From) {
+			return errors.Errorf("'dateRanges' %s has to end after it starts", dateRange)
+		}
+	}
+	for _, window := range calendar.WeeklyWindows {
+		if len(window.Days) == 0 {
+			return errors.New("'weeklyWindows.days' is required")
+		}
+		for _, day := range window.Days {
+			if _, exists := weekdays[day]; !exists {
+				return errors.Errorf("'weeklyWindows.days' %s is invalid", day)
+			}
+		}
+		_, err = parseTimeOfDay(window.StartTime)
+		if err != nil {
+			return errors.Wrap(err, "'weeklyWindows.startTime' is invalid")
+		}
+		_, err = parseTimeOfDay(window.EndTime)
+		if err != nil {
+			return errors.Wrap(err, "'weeklyWindows.endTime' is invalid")
+		}
+	}
+	calendar.LastUpdate = time.Now()
+	return nil
+}
+
+func (dateRange DateRange) String() string {
+	result := fmt.Sprintf("%s - %s", dateRange.From.Format(time.RFC3339), dateRange.To.Format(time.RFC3339))
+	if dateRange.Description != "" {
+		result = fmt.Sprintf("%s (%s)", dateRange.Description, result)
+	}
+	return result
+}
+
+func (window WeeklyWindow) String() string {
+	return fmt.Sprintf("%s %s-%s", strings.Join(window.Days, ","), window.StartTime, window.EndTime)
+}
+
+// parseTimeOfDay returns minutes since midnight of HH:MM time, 24:00 is allowed as the end of a day
+func parseTimeOfDay(value string) (int, error) {
+	var hours, minutes int
+	_, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes)
+	if err != nil || len(value) != 5 {
+		return 0, errors.Errorf("time %s does not match HH:MM", value)
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes > 0) {
+		return 0, errors.Errorf("time %s is out of range", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// Match returns description of the date range or weekly window containing t, empty string if there is none
+func (calendar *Calendar) Match(t time.Time) string {
+	for _, dateRange := range calendar.DateRanges {
+		if !t.Before(dateRange.From) && t.Before(dateRange.To) {
+			return dateRange.String()
+		}
+	}
+	location, err := calendar.Location()
+	if err != nil {
+		location = time.Local
+	}
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+	for _, window := range calendar.WeeklyWindows {
+		start, _ := parseTimeOfDay(window.StartTime)
+		end, _ := parseTimeOfDay(window.EndTime)
+		if end > start {
+			if window.hasDay(today) && minute >= start && minute < end {
+				return window.String()
+			}
+		} else if (window.hasDay(today) && minute >= start) || (window.hasDay(yesterday) && minute < end) {
+			return window.String()
+		}
+	}
+	return ""
+}
+
+func (window WeeklyWindow) hasDay(day time.Weekday) bool {
+	for _, name := range window.Days {
+		if weekdays[name] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Calendars returns names of calendars included or excluded by the schedule
+func (schedule *Schedule) Calendars() []string {
+	names := make([]string, 0, len(schedule.IncludeCalendars)+len(schedule.ExcludeCalendars))
+	names = append(names, schedule.IncludeCalendars...)
+	return append(names, schedule.ExcludeCalendars...)
+}
+
+// UsesCalendar returns true if the schedule includes or excludes the named calendar
+func (schedule *Schedule) UsesCalendar(calendarName string) bool {
+	for _, name := range schedule.Calendars() {
+		if name == calendarName {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCalendars returns reason why the schedule cannot fire at fireTime according to its calendars,
+// empty string if it can. Calendars are looked up by name, a missing included calendar never allows firing.
+func (schedule *Schedule) CheckCalendars(fireTime time.Time, calendars map[string]Calendar) string {
+	for _, name := range schedule.ExcludeCalendars {
+		calendar, exists := calendars[name]
+		if !exists {
+			continue
+		}
+		if match := calendar.Match(fireTime); match != "" {
+			return fmt.Sprintf("excluded by calendar %s: %s", name, match)
+		}
+	}
+	if len(schedule.IncludeCalendars) == 0 {
+		return ""
+	}
+	for _, name := range schedule.IncludeCalendars {
+		calendar, exists := calendars[name]
+		if exists && calendar.Match(fireTime) != "" {
+			return ""
+		}
+	}
+	return fmt.Sprintf("not included by calendars %s", strings.Join(schedule.IncludeCalendars, ","))
+}
+
+func (schedule *Schedule) validateCalendars() error {
+	for _, names := range [][]string{schedule.IncludeCalendars, schedule.ExcludeCalendars} {
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if name == "" {
+				return errors.New("calendar name cannot be empty")
+			}
+			if seen[name] {
+				return errors.Errorf("duplicate calendar %s", name)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package ifc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCalendars() map[string]Calendar {
+	return map[string]Calendar{
+		"freeze": {
+			Name:     "freeze",
+			TimeZone: "UTC",
+			DateRanges: []DateRange{{
+				From:        time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC),
+				To:          time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Description: "year end freeze",
+			}},
+		},
+		"maintenance": {
+			Name:     "maintenance",
+			TimeZone: "UTC",
+			WeeklyWindows: []WeeklyWindow{
+				{Days: []string{"SATURDAY"}, StartTime: "22:00", EndTime: "02:00"},
+				{Days: []string{"TUESDAY", "THURSDAY"}, StartTime: "01:00", EndTime: "03:00"},
+			},
+		},
+	}
+}
+
+func TestCalendarMatch(t *testing.T) {
+	calendars := testCalendars()
+	freeze := calendars["freeze"]
+	maintenance := calendars["maintenance"]
+	tests := []struct {
+		calendar Calendar
+		time     time.Time
+		matches  bool
+	}{
+		{freeze, time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC), true},
+		{freeze, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{freeze, time.Date(2023, 12, 19, 23, 59, 0, 0, time.UTC), false},
+		// Saturday 2023-05-06
+		{maintenance, time.Date(2023, 5, 6, 22, 0, 0, 0, time.UTC), true},
+		{maintenance, time.Date(2023, 5, 7, 1, 59, 0, 0, time.UTC), true},
+		{maintenance, time.Date(2023, 5, 7, 2, 0, 0, 0, time.UTC), false},
+		{maintenance, time.Date(2023, 5, 6, 1, 0, 0, 0, time.UTC), false},
+		{maintenance, time.Date(2023, 5, 9, 2, 30, 0, 0, time.UTC), true},
+		{maintenance, time.Date(2023, 5, 10, 2, 30, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		match := test.calendar.Match(test.time)
+		if (match != "") != test.matches {
+			t.Errorf("Unexpected match of %s at %s: '%s'", test.calendar.Name, test.time, match)
+		}
+	}
+}
+
+func TestCheckCalendars(t *testing.T) {
+	calendars := testCalendars()
+	schedule := Schedule{ExcludeCalendars: []string{"freeze", "missing"}}
+	reason := schedule.CheckCalendars(time.Date(2023, 12, 24, 12, 0, 0, 0, time.UTC), calendars)
+	if !strings.Contains(reason, "year end freeze") {
+		t.Errorf("Unexpected reason '%s'", reason)
+	}
+	if reason = schedule.CheckCalendars(time.Date(2023, 12, 10, 12, 0, 0, 0, time.UTC), calendars); reason != "" {
+		t.Errorf("Unexpected reason '%s'", reason)
+	}
+
+	schedule = Schedule{IncludeCalendars: []string{"maintenance"}}
+	if reason = schedule.CheckCalendars(time.Date(2023, 5, 6, 23, 0, 0, 0, time.UTC), calendars); reason != "" {
+		t.Errorf("Unexpected reason '%s'", reason)
+	}
+	if reason = schedule.CheckCalendars(time.Date(2023, 5, 6, 12, 0, 0, 0, time.UTC), calendars); reason == "" {
+		t.Errorf("Expected fire time outside of included calendar to be forbidden")
+	}
+}
+
+func TestNextAllowedFireTimes(t *testing.T) {
+	schedule := Schedule{
+		Enabled:          true,
+		CronString:       "0 12 * * *",
+		TimeZone:         "UTC",
+		ExcludeCalendars: []string{"freeze"},
+	}
+	from := time.Date(2023, 12, 18, 0, 0, 0, 0, time.UTC)
+	fireTimes, err := schedule.NextAllowedFireTimes(from, 3, testCalendars())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2023, 12, 18, 12, 0, 0, 0, time.UTC),
+		time.Date(2023, 12, 19, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+	}
+	if len(fireTimes) != len(expected) {
+		t.Fatalf("Unexpected fire times %v", fireTimes)
+	}
+	for i := range expected {
+		if !fireTimes[i].Equal(expected[i]) {
+			t.Errorf("Unexpected fire time %s, expected %s", fireTimes[i], expected[i])
+		}
+	}
+
+	schedule.IncludeCalendars = []string{"missing"}
+	fireTimes, err = schedule.NextAllowedFireTimes(from, 3, testCalendars())
+	if err != nil || len(fireTimes) != 0 {
+		t.Errorf("Unexpected fire times %v. err=%v", fireTimes, err)
+	}
+}
+
+func TestCalendarValidateAndUpdate(t *testing.T) {
+	calendar := testCalendars()["maintenance"]
+	if err := calendar.ValidateAndUpdate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	invalid := []Calendar{
+		{},
+		{Name: "a/b"},
+		{Name: "tz", TimeZone: "Nowhere/City"},
+		{Name: "range", DateRanges: []DateRange{{From: time.Now(), To: time.Now().Add(-time.Hour)}}},
+		{Name: "days", WeeklyWindows: []WeeklyWindow{{StartTime: "01:00", EndTime: "02:00"}}},
+		{Name: "day", WeeklyWindows: []WeeklyWindow{{Days: []string{"SOMEDAY"}, StartTime: "01:00", EndTime: "02:00"}}},
+		{Name: "time", WeeklyWindows: []WeeklyWindow{{Days: []string{"MONDAY"}, StartTime: "1:00", EndTime: "02:00"}}},
+		{Name: "range", WeeklyWindows: []WeeklyWindow{{Days: []string{"MONDAY"}, StartTime: "01:00", EndTime: "24:30"}}},
+	}
+	for _, calendar := range invalid {
+		if err := calendar.ValidateAndUpdate(); err == nil {
+			t.Errorf("Expected error for %v", calendar)
+		}
+	}
+}
+
+func TestParseICal(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20231225\r\n" +
+		"DTEND;VALUE=DATE:20231227\r\n" +
+		"SUMMARY:Christmas\\, St. Stephen\r\n" +
+		" 's Day\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20240101\r\n" +
+		"SUMMARY:New Year\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=Europe/Bratislava:20240105T220000\r\n" +
+		"DTEND:20240106T010000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	dateRanges, err := ParseICal(data, time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	bratislava, _ := time.LoadLocation("Europe/Bratislava")
+	expected := []DateRange{
+		{From: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), To: time.Date(2023, 12, 27, 0, 0, 0, 0, time.UTC), Description: "Christmas, St. Stephen's Day"},
+		{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Description: "New Year"},
+		{From: time.Date(2024, 1, 5, 22, 0, 0, 0, bratislava), To: time.Date(2024, 1, 6, 1, 0, 0, 0, time.UTC)},
+	}
+	if len(dateRanges) != len(expected) {
+		t.Fatalf("Unexpected date ranges %v", dateRanges)
+	}
+	for i := range expected {
+		if !dateRanges[i].From.Equal(expected[i].From) || !dateRanges[i].To.Equal(expected[i].To) ||
+			dateRanges[i].Description != expected[i].Description {
+			t.Errorf("Unexpected date range %v, expected %v", dateRanges[i], expected[i])
+		}
+	}
+
+	_, err = ParseICal("BEGIN:VEVENT\nDTSTART:20240101\nRRULE:FREQ=YEARLY\nEND:VEVENT\n", time.UTC)
+	if err == nil {
+		t.Errorf("Expected error for recurring event")
+	}
+	_, err = ParseICal("BEGIN:VEVENT\nDTSTART:2024-01-01\nEND:VEVENT\n", time.UTC)
+	if err == nil {
+		t.Errorf("Expected error for invalid date")
+	}
+}
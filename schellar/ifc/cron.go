@@ -0,0 +1,140 @@
+package ifc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Cron formats select syntax of the cron string
+const (
+	// five fields (minute, hour, day of month, month, day of week) or a descriptor
+	CronFormatStandard = "STANDARD"
+	// six fields with seconds first or a descriptor
+	CronFormatWithSeconds = "WITH_SECONDS"
+	// only descriptors like @hourly or @every 30s
+	CronFormatDescriptor = "DESCRIPTOR"
+)
+
+var cronParsers = map[string]cron.Parser{
+	CronFormatStandard:    cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor),
+	CronFormatWithSeconds: cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor),
+	CronFormatDescriptor:  cron.NewParser(cron.Descriptor),
+}
+
+// Location returns time zone in which the cron string is evaluated, server time zone if not set
+func (schedule *Schedule) Location() (*time.Location, error) {
+	if schedule.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(schedule.TimeZone)
+}
+
+// ParseCron parses the cron string of the schedule according to its format in its time zone
+func (schedule *Schedule) ParseCron() (cron.Schedule, error) {
+	location, err := schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+	format := schedule.CronFormat
+	if format == "" {
+		format = CronFormatStandard
+	}
+	parser, exists := cronParsers[format]
+	if !exists {
+		return nil, fmt.Errorf("unknown cron format %s", format)
+	}
+	cronSchedule, err := parser.Parse(schedule.CronString)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := cronSchedule.(*cron.SpecSchedule); ok && schedule.TimeZone != "" {
+		spec.Location = location
+	}
+	return cronSchedule, nil
+}
+
+// FireTimesBetween returns fire times of the schedule after from up to to (including).
+// Only the latest max fire times are returned. Schedules triggered by upstream schedules have no fire times.
+func (schedule *Schedule) FireTimesBetween(from time.Time, to time.Time, max int) ([]time.Time, error) {
+	if schedule.IsUpstreamTriggered() {
+		return make([]time.Time, 0), nil
+	}
+	cronSchedule, err := schedule.ParseCron()
+	if err != nil {
+		return nil, err
+	}
+	first := cronSchedule.Next(from)
+	if first.IsZero() || first.After(to) || max <= 0 {
+		return make([]time.Time, 0), nil
+	}
+	// walk only a window before to, which is widened until it covers max fire times,
+	// so that frequent schedules do not iterate over every fire time of a long gap
+	gap := to.Sub(from)
+	if second := cronSchedule.Next(first); !second.IsZero() && second.After(first) && second.Sub(first) < gap/time.Duration(max) {
+		for window := time.Duration(max) * second.Sub(first); window < gap; window *= 2 {
+			if fireTimes := latestFireTimes(cronSchedule, to.Add(-window), to, max); len(fireTimes) == max {
+				return fireTimes, nil
+			}
+			if window > gap/2 {
+				break
+			}
+		}
+	}
+	return latestFireTimes(cronSchedule, from, to, max), nil
+}
+
+// latestFireTimes returns the latest max fire times after from up to to (including)
+func latestFireTimes(cronSchedule cron.Schedule, from time.Time, to time.Time, max int) []time.Time {
+	fireTimes := make([]time.Time, 0)
+	for fireTime := cronSchedule.Next(from); !fireTime.IsZero() && !fireTime.After(to); fireTime = cronSchedule.Next(fireTime) {
+		fireTimes = append(fireTimes, fireTime)
+		if len(fireTimes) > max {
+			fireTimes = fireTimes[1:]
+		}
+	}
+	return fireTimes
+}
+
+// NextFireTimes returns up to count fire times after from within activation dates of the schedule.
+// Disabled schedule and schedule triggered by upstream schedules do not fire by time at all.
+func (schedule *Schedule) NextFireTimes(from time.Time, count int) ([]time.Time, error) {
+	return schedule.NextAllowedFireTimes(from, count, nil)
+}
+
+// NextAllowedFireTimes returns up to count fire times like NextFireTimes, skipping fire times
+// forbidden by calendars of the schedule. Calendars are ignored when nil.
+// Fire times are shifted by hash offset of the schedule, random jitter is not known in advance.
+func (schedule *Schedule) NextAllowedFireTimes(from time.Time, count int, calendars map[string]Calendar) ([]time.Time, error) {
+	if schedule.IsUpstreamTriggered() {
+		return make([]time.Time, 0), nil
+	}
+	cronSchedule, err := schedule.ParseCron()
+	if err != nil {
+		return nil, err
+	}
+	fireTimes := make([]time.Time, 0, count)
+	if !schedule.Enabled {
+		return fireTimes, nil
+	}
+	if schedule.FromDate != nil && schedule.FromDate.After(from) {
+		from = *schedule.FromDate
+	}
+	candidates := 0
+	for fireTime := cronSchedule.Next(from); !fireTime.IsZero() && len(fireTimes) < count; fireTime = cronSchedule.Next(fireTime) {
+		if schedule.ToDate != nil && !fireTime.Before(*schedule.ToDate) {
+			break
+		}
+		// include calendars may never match
+		candidates++
+		if candidates > maxCalendarCandidates {
+			break
+		}
+		if calendars != nil && schedule.CheckCalendars(fireTime, calendars) != "" {
+			continue
+		}
+		fireTimes = append(fireTimes, fireTime.Add(schedule.PreviewOffset()))
+	}
+	return fireTimes, nil
+}
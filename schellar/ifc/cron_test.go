@@ -0,0 +1,150 @@
+package ifc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFireTimesBetween(t *testing.T) {
+	from := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 4, 30, 0, 0, time.UTC)
+	schedule := Schedule{CronString: "0 * * * *"}
+
+	actual, err := schedule.FireTimesBetween(from, to, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC),
+	}
+	assertTimes(t, expected, actual)
+
+	// only the latest are kept
+	actual, err = schedule.FireTimesBetween(from, to, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertTimes(t, expected[1:], actual)
+}
+
+func TestFireTimesBetweenTimeZone(t *testing.T) {
+	// daylight saving time starts on 2024-03-31 in Europe/Bratislava
+	from := time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	schedule := Schedule{CronString: "0 9 * * *", TimeZone: "Europe/Bratislava"}
+
+	actual, err := schedule.FireTimesBetween(from, to, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC),
+	}
+	assertTimes(t, expected, actual)
+}
+
+func TestFireTimesBetweenLongGap(t *testing.T) {
+	to := time.Date(2024, 1, 1, 12, 0, 0, 500, time.UTC)
+	from := to.AddDate(-10, 0, 0)
+	schedule := Schedule{CronString: "@every 1s", CronFormat: CronFormatWithSeconds}
+
+	started := time.Now()
+	actual, err := schedule.FireTimesBetween(from, to, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("Expected only the latest fire times computed, took %s", elapsed)
+	}
+	if len(actual) != 1000 || !actual[999].Equal(to.Add(-500)) || !actual[0].Equal(to.Add(-500-999*time.Second)) {
+		t.Fatalf("Unexpected fire times %v .. %v", actual[0], actual[len(actual)-1])
+	}
+
+	// irregular schedule returns the same fire times as walking the whole gap
+	schedule = Schedule{CronString: "0 9 * * 1-5"}
+	cronSchedule, _ := schedule.ParseCron()
+	for _, max := range []int{1, 3, 7, 100} {
+		actual, err = schedule.FireTimesBetween(to.AddDate(-1, 0, 0), to, max)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertTimes(t, latestFireTimes(cronSchedule, to.AddDate(-1, 0, 0), to, max), actual)
+	}
+}
+
+func TestInvalidTimeZone(t *testing.T) {
+	schedule := Schedule{CronString: "0 9 * * *", TimeZone: "Mars/Olympus_Mons"}
+	_, err := schedule.ParseCron()
+	if err == nil {
+		t.Fatalf("Expected error for invalid time zone")
+	}
+}
+
+func TestCronFormats(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Minute)
+	tests := []struct {
+		cronFormat string
+		cronString string
+		valid      bool
+		count      int
+	}{
+		{CronFormatStandard, "* * * * *", true, 1},
+		{CronFormatStandard, "*/30 * * * * *", false, 0},
+		{CronFormatWithSeconds, "*/30 * * * * *", true, 2},
+		{CronFormatWithSeconds, "* * * * *", false, 0},
+		{CronFormatWithSeconds, "@every 15s", true, 4},
+		{CronFormatDescriptor, "@every 30s", true, 2},
+		{CronFormatDescriptor, "* * * * *", false, 0},
+		{"UNKNOWN", "* * * * *", false, 0},
+	}
+	for _, test := range tests {
+		schedule := Schedule{CronString: test.cronString, CronFormat: test.cronFormat}
+		actual, err := schedule.FireTimesBetween(from, to, 10)
+		if (err == nil) != test.valid {
+			t.Fatalf("Unexpected validity of %s %s: err=%v", test.cronFormat, test.cronString, err)
+		}
+		if len(actual) != test.count {
+			t.Fatalf("Unexpected fire times of %s %s: %v", test.cronFormat, test.cronString, actual)
+		}
+	}
+}
+
+func TestNextFireTimes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	fromDate := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	toDate := time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)
+	schedule := Schedule{CronString: "0 * * * *", Enabled: true, FromDate: &fromDate, ToDate: &toDate}
+
+	actual, err := schedule.NextFireTimes(now, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC),
+	}
+	assertTimes(t, expected, actual)
+
+	schedule.Enabled = false
+	actual, err = schedule.NextFireTimes(now, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertTimes(t, []time.Time{}, actual)
+}
+
+func assertTimes(t *testing.T, expected []time.Time, actual []time.Time) {
+	if len(actual) != len(expected) {
+		t.Fatalf("Unexpected: %v, should be %v", actual, expected)
+	}
+	for i := range expected {
+		if !actual[i].Equal(expected[i]) {
+			t.Fatalf("Unexpected: %v, should be %v", actual, expected)
+		}
+	}
+}
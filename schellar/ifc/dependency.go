@@ -0,0 +1,114 @@
+package ifc
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Trigger modes decide what fires the schedule
+const (
+	// TriggerModeCron fires the schedule by its cron string
+	TriggerModeCron = "CRON"
+	// TriggerModeUpstream fires the schedule whenever a workflow of a schedule it depends on finishes
+	TriggerModeUpstream = "UPSTREAM"
+)
+
+// Dependency conditions decide which latest finished execution of the upstream schedule satisfies the dependency
+const (
+	DependencyCompleted   = "COMPLETED"
+	DependencyAnyTerminal = "ANY_TERMINAL"
+)
+
+// Dependency of a schedule on the latest finished execution of another schedule
+type Dependency struct {
+	ScheduleName string `json:"scheduleName" bson:"scheduleName"`
+	Condition    string `json:"condition,omitempty" bson:"condition"`
+	// WithinMinutes requires the upstream execution to have finished at most this many minutes ago, 0 means any time
+	WithinMinutes int `json:"withinMinutes,omitempty" bson:"withinMinutes"`
+}
+
+// IsTerminalStatus returns true for statuses of finished workflows
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case "COMPLETED", "FAILED", "TERMINATED", "TIMED_OUT":
+		return true
+	}
+	return false
+}
+
+// IsUpstreamTriggered returns true if the schedule is fired by its upstream schedules instead of the cron string
+func (schedule *Schedule) IsUpstreamTriggered() bool {
+	return schedule.TriggerMode == TriggerModeUpstream
+}
+
+// DependsOnSchedule returns true if the schedule has a dependency on the named schedule
+func (schedule *Schedule) DependsOnSchedule(scheduleName string) bool {
+	for _, dependency := range schedule.DependsOn {
+		if dependency.ScheduleName == scheduleName {
+			return true
+		}
+	}
+	return false
+}
+
+func (schedule *Schedule) validateDependencies() error {
+	switch schedule.TriggerMode {
+	case "":
+		schedule.TriggerMode = TriggerModeCron
+	case TriggerModeCron:
+	case TriggerModeUpstream:
+		if len(schedule.DependsOn) == 0 {
+			return errors.New("'dependsOn' is required with UPSTREAM 'triggerMode'")
+		}
+	default:
+		return errors.Errorf("'triggerMode' %s is invalid", schedule.TriggerMode)
+	}
+	seen := make(map[string]bool)
+	for i := range schedule.DependsOn {
+		dependency := &schedule.DependsOn[i]
+		if dependency.ScheduleName == "" {
+			return errors.New("'dependsOn.scheduleName' is required")
+		}
+		if dependency.ScheduleName == schedule.Name {
+			return errors.New("schedule cannot depend on itself")
+		}
+		if seen[dependency.ScheduleName] {
+			return errors.Errorf("duplicate dependency on schedule %s", dependency.ScheduleName)
+		}
+		seen[dependency.ScheduleName] = true
+		switch dependency.Condition {
+		case "":
+			dependency.Condition = DependencyCompleted
+		case DependencyCompleted, DependencyAnyTerminal:
+		default:
+			return errors.Errorf("'dependsOn.condition' %s is invalid", dependency.Condition)
+		}
+		if dependency.WithinMinutes < 0 {
+			return errors.New("'dependsOn.withinMinutes' cannot be negative")
+		}
+	}
+	return nil
+}
+
+// SatisfiedBy checks the dependency against the latest finished execution of the upstream schedule (nil if there is none).
+// Returns nil when satisfied, otherwise the reason why not.
+func (dependency Dependency) SatisfiedBy(latest *Execution, now time.Time) error {
+	if latest == nil {
+		return errors.Errorf("schedule %s has no finished workflow", dependency.ScheduleName)
+	}
+	if dependency.Condition != DependencyAnyTerminal && latest.Status != "COMPLETED" {
+		return errors.Errorf("latest workflow %s of schedule %s is %s", latest.WorkflowID, dependency.ScheduleName, latest.Status)
+	}
+	if dependency.WithinMinutes > 0 {
+		endTime := latest.EndTime
+		if endTime == nil {
+			endTime = latest.StartTime
+		}
+		if endTime == nil || now.Sub(*endTime) > time.Duration(dependency.WithinMinutes)*time.Minute {
+			return errors.Errorf("latest workflow %s of schedule %s did not finish within %d minutes",
+				latest.WorkflowID, dependency.ScheduleName, dependency.WithinMinutes)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package ifc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDependencySatisfiedBy(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Minute)
+	old := now.Add(-2 * time.Hour)
+	completed := &Execution{WorkflowID: "1", Status: "COMPLETED", EndTime: &recent}
+	failed := &Execution{WorkflowID: "2", Status: "FAILED", EndTime: &recent}
+	oldCompleted := &Execution{WorkflowID: "3", Status: "COMPLETED", EndTime: &old}
+
+	tests := []struct {
+		dependency Dependency
+		latest     *Execution
+		satisfied  bool
+	}{
+		{Dependency{ScheduleName: "a", Condition: DependencyCompleted}, completed, true},
+		{Dependency{ScheduleName: "a", Condition: DependencyCompleted}, failed, false},
+		{Dependency{ScheduleName: "a", Condition: DependencyCompleted}, nil, false},
+		{Dependency{ScheduleName: "a", Condition: DependencyAnyTerminal}, failed, true},
+		{Dependency{ScheduleName: "a", Condition: DependencyCompleted, WithinMinutes: 30}, completed, true},
+		{Dependency{ScheduleName: "a", Condition: DependencyCompleted, WithinMinutes: 30}, oldCompleted, false},
+	}
+	for i, test := range tests {
+		err := test.dependency.SatisfiedBy(test.latest, now)
+		if (err == nil) != test.satisfied {
+			t.Fatalf("Test %d: unexpected result %v", i, err)
+		}
+	}
+}
+
+func TestValidateUpstreamTriggerMode(t *testing.T) {
+	schedule := Schedule{Name: "name", WorkflowName: "workflow", TriggerMode: TriggerModeUpstream}
+	if err := schedule.ValidateAndUpdate(); err == nil {
+		t.Fatalf("Expected error for UPSTREAM trigger mode without dependencies")
+	}
+	schedule.DependsOn = []Dependency{{ScheduleName: "upstream"}}
+	if err := schedule.ValidateAndUpdate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schedule.DependsOn[0].Condition != DependencyCompleted {
+		t.Fatalf("Unexpected default condition %s", schedule.DependsOn[0].Condition)
+	}
+	fireTimes, err := schedule.NextFireTimes(time.Now(), 5)
+	if err != nil || len(fireTimes) != 0 {
+		t.Fatalf("Unexpected fire times of upstream triggered schedule: %v %v", fireTimes, err)
+	}
+
+	schedule.DependsOn = []Dependency{{ScheduleName: "name"}}
+	if err := schedule.ValidateAndUpdate(); err == nil {
+		t.Fatalf("Expected error for dependency on itself")
+	}
+}
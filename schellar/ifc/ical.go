@@ -0,0 +1,116 @@
+package ifc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseICal imports events of an iCalendar (RFC 5545) document as date ranges. All day events and
+// times without time zone are evaluated in location. Recurring events are not supported.
+func ParseICal(data string, location *time.Location) ([]DateRange, error) {
+	dateRanges := make([]DateRange, 0)
+	var (
+		inEvent     bool
+		start, end  *time.Time
+		allDay      bool
+		description string
+	)
+	for number, line := range unfoldICalLines(data) {
+		name, params, value := splitICalLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, start, end, allDay, description = true, nil, nil, false, ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent || start == nil {
+				return nil, errors.Errorf("event ending on line %d has no DTSTART", number+1)
+			}
+			if end == nil {
+				// events without end last one day (all day events) or are instant
+				next := *start
+				if allDay {
+					next = start.AddDate(0, 0, 1)
+				}
+				end = &next
+			}
+			if end.After(*start) {
+				dateRanges = append(dateRanges, DateRange{From: *start, To: *end, Description: description})
+			}
+			inEvent = false
+		case !inEvent:
+		case name == "DTSTART" || name == "DTEND":
+			t, date, err := parseICalTime(params, value, location)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s on line %d", name, number+1)
+			}
+			if name == "DTSTART" {
+				start, allDay = &t, date
+			} else {
+				end = &t
+			}
+		case name == "SUMMARY":
+			description = unescapeICalText(value)
+		case name == "RRULE" || name == "RDATE":
+			return nil, errors.Errorf("recurring event on line %d is not supported", number+1)
+		}
+	}
+	if inEvent {
+		return nil, errors.New("event is not ended")
+	}
+	return dateRanges, nil
+}
+
+// unfoldICalLines joins continuation lines starting with a space or a tab
+func unfoldICalLines(data string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// splitICalLine splits content line like DTSTART;TZID=Europe/Bratislava:20231224T000000
+func splitICalLine(line string) (string, map[string]string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if eq := strings.Index(param, "="); eq > 0 {
+			params[strings.ToUpper(param[:eq])] = strings.Trim(param[eq+1:], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// parseICalTime returns the time and whether it is a date without time of day
+func parseICalTime(params map[string]string, value string, location *time.Location) (time.Time, bool, error) {
+	if tzid, exists := params["TZID"]; exists {
+		tzLocation, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		location = tzLocation
+	}
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, location)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	return t, false, err
+}
+
+func unescapeICalText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
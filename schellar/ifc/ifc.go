@@ -5,10 +5,9 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/robfig/cron/v3"
 )
 
-//Schedule struct data
+// Schedule struct data
 type Schedule struct {
 	Name                string                 `json:"name,omitempty" bson:"name"`
 	Enabled             bool                   `json:"enabled,omitempty" bson:"enabled"`
@@ -24,9 +23,91 @@ type Schedule struct {
 	LastUpdate          time.Time              `json:"lastUpdate,omitempty" bson:"lastUpdate"`
 	CorrelationID       string                 `json:"correlationId,omitempty" bson:"correlationId"`
 	TaskToDomain        map[string]string      `json:"taskToDomain,omitempty" bson:"taskToDomain"`
+	MisfirePolicy       string                 `json:"misfirePolicy,omitempty" bson:"misfirePolicy"`
+	MisfireMaxCount     int                    `json:"misfireMaxCount,omitempty" bson:"misfireMaxCount"`
+	LastFireTime        *time.Time             `json:"lastFireTime,omitempty" bson:"lastFireTime"`
+	TimeZone            string                 `json:"timeZone,omitempty" bson:"timeZone"`
+	CronFormat          string                 `json:"cronFormat,omitempty" bson:"cronFormat"`
+	Condition           string                 `json:"condition,omitempty" bson:"condition"`
+	ConditionMessage    string                 `json:"conditionMessage,omitempty" bson:"conditionMessage"`
+	MaxRunDuration      int                    `json:"maxRunDuration,omitempty" bson:"maxRunDuration"`
+	OnOverrun           string                 `json:"onOverrun,omitempty" bson:"onOverrun"`
+	RetryPolicy         RetryPolicy            `json:"retryPolicy,omitempty" bson:"retryPolicy"`
+	OnFailure           string                 `json:"onFailure,omitempty" bson:"onFailure"`
+	OnFailureMaxCount   int                    `json:"onFailureMaxCount,omitempty" bson:"onFailureMaxCount"`
+	ConcurrencyPolicy   string                 `json:"concurrencyPolicy,omitempty" bson:"concurrencyPolicy"`
+	MaxConcurrentRuns   int                    `json:"maxConcurrentRuns,omitempty" bson:"maxConcurrentRuns"`
+	TriggerMode         string                 `json:"triggerMode,omitempty" bson:"triggerMode"`
+	DependsOn           []Dependency           `json:"dependsOn,omitempty" bson:"dependsOn"`
+	// OutputMapping maps workflow context keys to paths selecting values from output of finished workflows
+	OutputMapping          map[string]string `json:"outputMapping,omitempty" bson:"outputMapping"`
+	UpdateContextOnFailure bool              `json:"updateContextOnFailure,omitempty" bson:"updateContextOnFailure"`
+	MaxContextBytes        int               `json:"maxContextBytes,omitempty" bson:"maxContextBytes"`
+	// IncludeCalendars restrict fire times to windows of any of these calendars
+	IncludeCalendars []string `json:"includeCalendars,omitempty" bson:"includeCalendars"`
+	// ExcludeCalendars forbid fire times within windows of any of these calendars
+	ExcludeCalendars []string `json:"excludeCalendars,omitempty" bson:"excludeCalendars"`
+	// JitterSeconds delays timer triggers up to this many seconds to spread workflows of schedules firing at the same time
+	JitterSeconds int    `json:"jitterSeconds,omitempty" bson:"jitterSeconds"`
+	JitterMode    string `json:"jitterMode,omitempty" bson:"jitterMode"`
+	// PausedBy, PauseReason, PausedAt and PausedUntil describe pause of a disabled schedule,
+	// it is enabled again once PausedUntil passes
+	PausedBy    string     `json:"pausedBy,omitempty" bson:"pausedBy"`
+	PauseReason string     `json:"pauseReason,omitempty" bson:"pauseReason"`
+	PausedAt    *time.Time `json:"pausedAt,omitempty" bson:"pausedAt"`
+	PausedUntil *time.Time `json:"pausedUntil,omitempty" bson:"pausedUntil"`
+	// Labels organize schedules (e.g. team, region) and select them by label selector
+	Labels map[string]string `json:"labels,omitempty" bson:"labels"`
+	// NextFireTime is the upcoming fire time stored by the leader so that schedules can be sorted by it, nil if there is none
+	NextFireTime *time.Time `json:"nextFireTime,omitempty" bson:"nextFireTime"`
+	// EnabledAt is when the disabled schedule was enabled again, timer triggers missed before it are not caught up
+	EnabledAt *time.Time `json:"enabledAt,omitempty" bson:"enabledAt"`
 }
 
-func (schedule Schedule) ValidateAndUpdate() error {
+// DefaultCheckWarningSeconds is used when schedule does not set how long its workflows may run without warning
+const DefaultCheckWarningSeconds = 3600
+
+// Schedule conditions report problems of a schedule independently of its workflow status
+const (
+	ConditionOK      = "OK"
+	ConditionWarning = "WARNING"
+)
+
+// Overrun actions decide what happens with workflows running longer than maxRunDuration of their schedule
+const (
+	OverrunNone                 = "NONE"
+	OverrunTerminate            = "TERMINATE"
+	OverrunTerminateAndRelaunch = "TERMINATE_AND_RELAUNCH"
+)
+
+// Failure actions decide what happens when a workflow launched by a schedule ends FAILED or TIMED_OUT
+const (
+	FailureNone     = "NONE"
+	FailureRetry    = "RETRY"
+	FailureRestart  = "RESTART"
+	FailureRelaunch = "RELAUNCH"
+)
+
+// Concurrency policies decide what happens with a trigger while workflows of the schedule are still running
+const (
+	// ConcurrencyAllow launches the workflow unless maxConcurrentRuns (if set) workflows are running
+	ConcurrencyAllow = "ALLOW"
+	// ConcurrencyForbid skips the trigger
+	ConcurrencyForbid = "FORBID"
+	// ConcurrencyReplace terminates the oldest running workflows before launching the new one
+	ConcurrencyReplace = "REPLACE"
+	// ConcurrencyQueue defers the trigger until a running workflow completes
+	ConcurrencyQueue = "QUEUE"
+)
+
+// Misfire policies decide what happens with timer triggers missed while schellar was not running
+const (
+	MisfireSkip     = "SKIP"
+	MisfireFireOnce = "FIRE_ONCE"
+	MisfireFireAll  = "FIRE_ALL"
+)
+
+func (schedule *Schedule) ValidateAndUpdate() error {
 	if schedule.Name == "" {
 		return errors.New("'name' is required")
 	}
@@ -36,31 +117,277 @@ func (schedule Schedule) ValidateAndUpdate() error {
 	if schedule.WorkflowName == "" {
 		return errors.New("'workflowName' is required")
 	}
-	if schedule.CronString == "" {
+	err := schedule.validateDependencies()
+	if err != nil {
+		return err
+	}
+	if schedule.CronString == "" && !schedule.IsUpstreamTriggered() {
 		return errors.New("'cronString' is required")
 	}
-	_, err := cron.ParseStandard(schedule.CronString)
+	_, err = schedule.Location()
+	if err != nil {
+		return errors.Wrap(err, "'timeZone' is invalid")
+	}
+	if schedule.CronFormat == "" {
+		schedule.CronFormat = CronFormatStandard
+	}
+	if _, exists := cronParsers[schedule.CronFormat]; !exists {
+		return errors.Errorf("'cronFormat' %s is invalid", schedule.CronFormat)
+	}
+	if schedule.CronString != "" {
+		_, err = schedule.ParseCron()
+		if err != nil {
+			return errors.Wrap(err, "'cronString' is invalid")
+		}
+	}
+	err = schedule.validateJitter()
+	if err != nil {
+		return err
+	}
+	err = schedule.validateCalendars()
+	if err != nil {
+		return err
+	}
+	err = schedule.validateLabels()
+	if err != nil {
+		return err
+	}
+	err = schedule.validateOutputMapping()
 	if err != nil {
-		return errors.Wrap(err, "'cronString' is invalid")
+		return err
+	}
+	err = ValidateContextTemplates(schedule.WorkflowContext)
+	if err != nil {
+		return errors.Wrap(err, "'workflowContext' template is invalid")
+	}
+	switch schedule.MisfirePolicy {
+	case "":
+		schedule.MisfirePolicy = MisfireSkip
+	case MisfireSkip, MisfireFireOnce:
+	case MisfireFireAll:
+		if schedule.MisfireMaxCount <= 0 {
+			return errors.New("'misfireMaxCount' has to be positive with FIRE_ALL 'misfirePolicy'")
+		}
+	default:
+		return errors.Errorf("'misfirePolicy' %s is invalid", schedule.MisfirePolicy)
+	}
+	if schedule.MisfireMaxCount < 0 {
+		return errors.New("'misfireMaxCount' cannot be negative")
+	}
+	if schedule.CheckWarningSeconds < 0 {
+		return errors.New("'checkWarningSeconds' cannot be negative")
 	}
 	if schedule.CheckWarningSeconds == 0 {
-		schedule.CheckWarningSeconds = 3600
+		schedule.CheckWarningSeconds = DefaultCheckWarningSeconds
+	}
+	if schedule.MaxRunDuration < 0 {
+		return errors.New("'maxRunDuration' cannot be negative")
+	}
+	switch schedule.OnOverrun {
+	case "":
+		schedule.OnOverrun = OverrunNone
+	case OverrunNone:
+	case OverrunTerminate, OverrunTerminateAndRelaunch:
+		if schedule.MaxRunDuration == 0 {
+			return errors.Errorf("'maxRunDuration' has to be positive with %s 'onOverrun'", schedule.OnOverrun)
+		}
+	default:
+		return errors.Errorf("'onOverrun' %s is invalid", schedule.OnOverrun)
+	}
+	if schedule.ConcurrencyPolicy == "" {
+		schedule.ConcurrencyPolicy = schedule.Concurrency()
+	}
+	switch schedule.ConcurrencyPolicy {
+	case ConcurrencyAllow, ConcurrencyForbid, ConcurrencyReplace, ConcurrencyQueue:
+	default:
+		return errors.Errorf("'concurrencyPolicy' %s is invalid", schedule.ConcurrencyPolicy)
+	}
+	if schedule.MaxConcurrentRuns < 0 {
+		return errors.New("'maxConcurrentRuns' cannot be negative")
+	}
+	if schedule.ConcurrencyPolicy == ConcurrencyForbid && schedule.MaxConcurrentRuns > 1 {
+		return errors.New("'maxConcurrentRuns' cannot be greater than 1 with FORBID 'concurrencyPolicy'")
+	}
+	// kept for clients reading the boolean flag
+	schedule.ParallelRuns = schedule.ConcurrencyPolicy == ConcurrencyAllow
+	if schedule.OnFailureMaxCount < 0 {
+		return errors.New("'onFailureMaxCount' cannot be negative")
+	}
+	switch schedule.OnFailure {
+	case "":
+		schedule.OnFailure = FailureNone
+	case FailureNone:
+	case FailureRetry, FailureRestart, FailureRelaunch:
+		if schedule.OnFailureMaxCount == 0 {
+			return errors.Errorf("'onFailureMaxCount' has to be positive with %s 'onFailure'", schedule.OnFailure)
+		}
+	default:
+		return errors.Errorf("'onFailure' %s is invalid", schedule.OnFailure)
+	}
+	err = schedule.RetryPolicy.validateAndUpdate()
+	if err != nil {
+		return err
+	}
+	if schedule.Condition == "" {
+		schedule.Condition = ConditionOK
+	}
+	if schedule.Enabled {
+		// enabled through update instead of resume
+		schedule.clearPause()
 	}
 	schedule.LastUpdate = time.Now()
 	return nil
 }
 
+// WarningThreshold returns how long workflows of the schedule may run before the schedule is marked with warning
+func (schedule *Schedule) WarningThreshold() time.Duration {
+	if schedule.CheckWarningSeconds <= 0 {
+		// schedules stored before the default was applied
+		return DefaultCheckWarningSeconds * time.Second
+	}
+	return time.Duration(schedule.CheckWarningSeconds) * time.Second
+}
+
+// Concurrency returns concurrency policy of the schedule,
+// schedules stored without it are derived from parallelRuns
+func (schedule *Schedule) Concurrency() string {
+	if schedule.ConcurrencyPolicy != "" {
+		return schedule.ConcurrencyPolicy
+	}
+	if schedule.ParallelRuns {
+		return ConcurrencyAllow
+	}
+	return ConcurrencyForbid
+}
+
+// ConcurrencyLimit returns how many workflows of the schedule may run at the same time, 0 means no limit
+func (schedule *Schedule) ConcurrencyLimit() int {
+	switch schedule.Concurrency() {
+	case ConcurrencyAllow:
+		return schedule.MaxConcurrentRuns
+	case ConcurrencyForbid:
+		return 1
+	}
+	if schedule.MaxConcurrentRuns > 0 {
+		return schedule.MaxConcurrentRuns
+	}
+	return 1
+}
+
+// IsOverrunning returns true when the execution started longer than maxRunDuration ago
+// and the schedule asks for an overrun action
+func (schedule *Schedule) IsOverrunning(execution Execution, now time.Time) bool {
+	if schedule.MaxRunDuration <= 0 || schedule.OnOverrun == "" || schedule.OnOverrun == OverrunNone {
+		return false
+	}
+	if execution.StartTime == nil {
+		return false
+	}
+	return now.Sub(*execution.StartTime) > time.Duration(schedule.MaxRunDuration)*time.Second
+}
+
+// Execution struct data, one record per workflow launched by a schedule
+type Execution struct {
+	ID           string     `json:"id,omitempty" bson:"id"`
+	ScheduleName string     `json:"scheduleName,omitempty" bson:"scheduleName"`
+	FireTime     time.Time  `json:"fireTime,omitempty" bson:"fireTime"`
+	Trigger      string     `json:"trigger,omitempty" bson:"trigger"`
+	WorkflowID   string     `json:"workflowId,omitempty" bson:"workflowId"`
+	Status       string     `json:"status,omitempty" bson:"status"`
+	StartTime    *time.Time `json:"startTime,omitempty" bson:"startTime"`
+	EndTime      *time.Time `json:"endTime,omitempty" bson:"endTime"`
+	Error        string     `json:"error,omitempty" bson:"error"`
+	// Attempts counts how many times the workflow launch was attempted
+	Attempts int `json:"attempts,omitempty" bson:"attempts"`
+	// Recoveries counts onFailure actions taken for the same fire time before this execution
+	Recoveries int `json:"recoveries,omitempty" bson:"recoveries"`
+	// WorkflowName of the schedule at the time of the trigger
+	WorkflowName string `json:"workflowName,omitempty" bson:"workflowName"`
+}
+
+// ExecutionFilter restricts execution queries, empty fields match everything
+type ExecutionFilter struct {
+	ScheduleName string
+	Status       string
+	// FiredBefore matches executions with fire time before it
+	FiredBefore *time.Time
+	// ExcludeStatus matches executions in any other status
+	ExcludeStatus string
+	WorkflowName  string
+}
+
+// Sort orders of schedule queries, schedules with equal values are ordered by name
+const (
+	ScheduleSortName       = "NAME"
+	ScheduleSortLastUpdate = "LAST_UPDATE"
+	// ScheduleSortNextRun orders by stored next fire time, schedules without it are last
+	ScheduleSortNextRun = "NEXT_RUN"
+	// ScheduleSortLastRun orders by last fire time, schedules without it are last
+	ScheduleSortLastRun = "LAST_RUN"
+	ScheduleSortStatus  = "STATUS"
+)
+
+// ScheduleFilter restricts and orders schedule queries, empty fields match everything
+type ScheduleFilter struct {
+	WorkflowName    string
+	WorkflowVersion string
+	LabelSelector   LabelSelector
+	// NamePrefix matches names starting with it (case sensitive)
+	NamePrefix string
+	// NameContains matches names containing it (case insensitive)
+	NameContains string
+	Enabled      *bool
+	Status       string
+	CronString   string
+	// ActiveAt matches schedules whose fromDate and toDate allow firing at that time
+	ActiveAt         *time.Time
+	LastUpdateAfter  *time.Time
+	LastUpdateBefore *time.Time
+	// SortBy is one of ScheduleSort constants, name by default
+	SortBy         string
+	SortDescending bool
+}
+
 type DB interface {
 	FindAll() ([]Schedule, error)
 	FindAllByWorkflowType(workflowName string, workflowId string) ([]Schedule, error)
 	FindAllByEnabled(enabled bool) ([]Schedule, error)
 	FindByName(scheduleName string) (*Schedule, error)
 	FindByStatus(status string) ([]Schedule, error)
+	// FindSchedules returns schedules matching the filter in its sort order
+	FindSchedules(filter ScheduleFilter) ([]Schedule, error)
 	UpdateStatus(scheduleName string, scheduleStatus string) error
 	UpdateStatusAndWorkflowContext(schedule Schedule) error
+	UpdateLastFireTime(scheduleName string, lastFireTime time.Time) error
+	UpdateNextFireTime(scheduleName string, nextFireTime *time.Time) error
+	UpdateCondition(scheduleName string, condition string, conditionMessage string) error
+	// ResumePause enables the schedule paused until pausedUntil, clears its pause and records enabledAt.
+	// Returns false when the schedule is not paused until pausedUntil anymore (resumed or paused again meanwhile).
+	ResumePause(scheduleName string, pausedUntil time.Time, enabledAt time.Time) (bool, error)
 	Insert(schedule Schedule) error
 	Update(schedule Schedule) error
 	RemoveByName(scheduleName string) error
+	InsertExecution(execution Execution) error
+	UpdateExecution(execution Execution) error
+	FindExecutionByWorkflowID(workflowID string) (*Execution, error)
+	// FindExecutions returns at most limit executions ordered from the most recent fire time,
+	// starting after the execution with ID after (if not empty). Zero limit means no limit
+	FindExecutions(filter ExecutionFilter, after string, limit int) ([]Execution, error)
+	CountExecutions(filter ExecutionFilter) (int, error)
+	// AcquireLease takes or renews the named lease for holder for ttl.
+	// Returns false when the lease is held by another holder and has not expired yet.
+	AcquireLease(leaseName string, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(leaseName string, holder string) error
+	// InsertContextVersion stores replaced workflow context, keeping at most keep latest versions of the schedule
+	InsertContextVersion(version ContextVersion, keep int) error
+	// FindContextVersions returns at most limit versions ordered from the latest one, zero limit means no limit
+	FindContextVersions(scheduleName string, limit int) ([]ContextVersion, error)
+	FindContextVersion(scheduleName string, version int) (*ContextVersion, error)
+	FindAllCalendars() ([]Calendar, error)
+	FindCalendarByName(calendarName string) (*Calendar, error)
+	InsertCalendar(calendar Calendar) error
+	UpdateCalendar(calendar Calendar) error
+	RemoveCalendarByName(calendarName string) error
 }
 
 type DBFactory interface {
@@ -0,0 +1,52 @@
+package ifc
+
+import "testing"
+
+func TestConcurrencyDerivedFromParallelRuns(t *testing.T) {
+	tests := []struct {
+		schedule Schedule
+		policy   string
+		limit    int
+	}{
+		{Schedule{ParallelRuns: true}, ConcurrencyAllow, 0},
+		{Schedule{ParallelRuns: false}, ConcurrencyForbid, 1},
+		{Schedule{ConcurrencyPolicy: ConcurrencyAllow, MaxConcurrentRuns: 3}, ConcurrencyAllow, 3},
+		{Schedule{ConcurrencyPolicy: ConcurrencyQueue}, ConcurrencyQueue, 1},
+		{Schedule{ConcurrencyPolicy: ConcurrencyReplace, MaxConcurrentRuns: 2}, ConcurrencyReplace, 2},
+	}
+	for _, test := range tests {
+		if policy := test.schedule.Concurrency(); policy != test.policy {
+			t.Fatalf("Unexpected policy of %+v: %s != %s", test.schedule, policy, test.policy)
+		}
+		if limit := test.schedule.ConcurrencyLimit(); limit != test.limit {
+			t.Fatalf("Unexpected limit of %+v: %d != %d", test.schedule, limit, test.limit)
+		}
+	}
+}
+
+func TestValidateConcurrencyPolicy(t *testing.T) {
+	schedule := Schedule{Name: "name", WorkflowName: "workflow", CronString: "* * * * *", ParallelRuns: true}
+	err := schedule.ValidateAndUpdate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schedule.ConcurrencyPolicy != ConcurrencyAllow {
+		t.Fatalf("Unexpected policy: %s", schedule.ConcurrencyPolicy)
+	}
+
+	schedule.ConcurrencyPolicy = ConcurrencyQueue
+	err = schedule.ValidateAndUpdate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schedule.ParallelRuns {
+		t.Fatalf("parallelRuns should be false with %s policy", schedule.ConcurrencyPolicy)
+	}
+
+	schedule.ConcurrencyPolicy = ConcurrencyForbid
+	schedule.MaxConcurrentRuns = 2
+	err = schedule.ValidateAndUpdate()
+	if err == nil {
+		t.Fatalf("Expected error for FORBID policy with maxConcurrentRuns=2")
+	}
+}
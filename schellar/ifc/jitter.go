@@ -0,0 +1,83 @@
+package ifc
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Jitter modes decide how timer triggers of the schedule are delayed up to jitterSeconds
+const (
+	// JitterRandom delays every timer trigger by a new random number of seconds
+	JitterRandom = "RANDOM"
+	// JitterHashOffset delays every timer trigger by the same number of seconds derived from the schedule name
+	JitterHashOffset = "HASH_OFFSET"
+)
+
+// maxJitterGaps is how many gaps between upcoming fire times are compared with jitterSeconds
+const maxJitterGaps = 10
+
+// HashOffset returns stable offset within jitterSeconds of the schedule, derived from its name
+func (schedule *Schedule) HashOffset() time.Duration {
+	if schedule.JitterSeconds <= 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(schedule.Name))
+	return time.Duration(hash.Sum32()%uint32(schedule.JitterSeconds+1)) * time.Second
+}
+
+// StartDelay returns how long a timer trigger of the schedule waits before launching the workflow
+func (schedule *Schedule) StartDelay() time.Duration {
+	if schedule.JitterSeconds <= 0 {
+		return 0
+	}
+	if schedule.JitterMode == JitterHashOffset {
+		return schedule.HashOffset()
+	}
+	return time.Duration(rand.Intn(schedule.JitterSeconds+1)) * time.Second
+}
+
+// PreviewOffset returns delay of timer triggers known in advance, zero for random jitter
+func (schedule *Schedule) PreviewOffset() time.Duration {
+	if schedule.JitterMode == JitterHashOffset {
+		return schedule.HashOffset()
+	}
+	return 0
+}
+
+func (schedule *Schedule) validateJitter() error {
+	switch schedule.JitterMode {
+	case "":
+		schedule.JitterMode = JitterRandom
+	case JitterRandom, JitterHashOffset:
+	default:
+		return errors.Errorf("'jitterMode' %s is invalid", schedule.JitterMode)
+	}
+	if schedule.JitterSeconds < 0 {
+		return errors.New("'jitterSeconds' cannot be negative")
+	}
+	if schedule.JitterSeconds == 0 || schedule.CronString == "" || schedule.IsUpstreamTriggered() {
+		return nil
+	}
+	cronSchedule, err := schedule.ParseCron()
+	if err != nil {
+		return err
+	}
+	// delayed trigger must not reach the next fire time
+	jitter := time.Duration(schedule.JitterSeconds) * time.Second
+	previous := cronSchedule.Next(time.Now())
+	for i := 0; i < maxJitterGaps && !previous.IsZero(); i++ {
+		next := cronSchedule.Next(previous)
+		if next.IsZero() {
+			break
+		}
+		if next.Sub(previous) <= jitter {
+			return errors.Errorf("'jitterSeconds' has to be shorter than %s between fire times", next.Sub(previous))
+		}
+		previous = next
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package ifc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHashOffset(t *testing.T) {
+	schedule := Schedule{Name: "backup", JitterSeconds: 600, JitterMode: JitterHashOffset}
+	offset := schedule.HashOffset()
+	if offset < 0 || offset > 600*time.Second || offset%time.Second != 0 {
+		t.Fatalf("Unexpected offset %s", offset)
+	}
+	for i := 0; i < 10; i++ {
+		if schedule.StartDelay() != offset {
+			t.Fatalf("Hash offset is not stable")
+		}
+	}
+	other := Schedule{Name: "inventory", JitterSeconds: 600, JitterMode: JitterHashOffset}
+	if other.HashOffset() == offset {
+		t.Errorf("Expected different offsets of different schedules")
+	}
+	if (&Schedule{Name: "backup"}).HashOffset() != 0 {
+		t.Errorf("Expected no offset without jitter")
+	}
+}
+
+func TestRandomStartDelay(t *testing.T) {
+	schedule := Schedule{Name: "backup", JitterSeconds: 5, JitterMode: JitterRandom}
+	for i := 0; i < 100; i++ {
+		delay := schedule.StartDelay()
+		if delay < 0 || delay > 5*time.Second {
+			t.Fatalf("Unexpected delay %s", delay)
+		}
+	}
+	if schedule.PreviewOffset() != 0 {
+		t.Errorf("Random jitter cannot be previewed")
+	}
+}
+
+func TestNextFireTimesWithHashOffset(t *testing.T) {
+	schedule := Schedule{Name: "backup", Enabled: true, CronString: "0 * * * *", JitterSeconds: 1800, JitterMode: JitterHashOffset}
+	from := time.Date(2023, 5, 1, 12, 30, 0, 0, time.UTC)
+	fireTimes, err := schedule.NextFireTimes(from, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	offset := schedule.HashOffset()
+	if len(fireTimes) != 2 || !fireTimes[0].Equal(time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC).Add(offset)) ||
+		fireTimes[1].Sub(fireTimes[0]) != time.Hour {
+		t.Errorf("Unexpected fire times %v with offset %s", fireTimes, offset)
+	}
+}
+
+func TestValidateJitter(t *testing.T) {
+	valid := Schedule{CronString: "0 * * * *", JitterSeconds: 3599}
+	if err := valid.validateJitter(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if valid.JitterMode != JitterRandom {
+		t.Errorf("Unexpected default jitter mode %s", valid.JitterMode)
+	}
+	invalid := []Schedule{
+		{CronString: "0 * * * *", JitterSeconds: 3600},
+		{CronString: "0 * * * *", JitterSeconds: -1},
+		{CronString: "0 * * * *", JitterMode: "SOMETIMES"},
+	}
+	for _, schedule := range invalid {
+		if err := schedule.validateJitter(); err == nil {
+			t.Errorf("Expected error for %v", schedule)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package ifc
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Label selector operators
+const (
+	LabelEquals    = "="
+	LabelNotEquals = "!="
+	LabelIn        = "in"
+	LabelNotIn     = "notin"
+	// LabelExists matches schedules having the label with any value
+	LabelExists = "exists"
+	// LabelNotExists matches schedules without the label
+	LabelNotExists = "!"
+)
+
+// maxLabelLength limits length of label keys and values
+const maxLabelLength = 63
+
+var (
+	// keys cannot contain '.' so that they can be used in Mongo field paths
+	labelKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_/-]*[A-Za-z0-9])?$`)
+	labelValuePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9_.-]*[A-Za-z0-9])?)?$`)
+	setRequirement    = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// LabelRequirement is a single condition of a label selector
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	// Values compared with the label, none for LabelExists and LabelNotExists
+	Values []string
+}
+
+// LabelSelector matches schedules whose labels satisfy all its requirements
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses comma separated requirements like "team=netops,region in (eu,us),tier!=test,!deprecated".
+// Empty selector matches all schedules.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	requirements := make(LabelSelector, 0)
+	for _, part := range splitRequirements(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if strings.TrimSpace(selector) == "" {
+				continue
+			}
+			return nil, errors.Errorf("empty requirement in label selector '%s'", selector)
+		}
+		requirement, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, nil
+}
+
+// splitRequirements splits the selector by commas outside of value sets
+func splitRequirements(selector string) []string {
+	parts := make([]string, 0)
+	depth := 0
+	start := 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+func parseRequirement(part string) (LabelRequirement, error) {
+	var requirement LabelRequirement
+	if match := setRequirement.FindStringSubmatch(part); match != nil {
+		requirement = LabelRequirement{Key: match[1], Operator: match[2], Values: make([]string, 0)}
+		for _, value := range strings.Split(match[3], ",") {
+			requirement.Values = append(requirement.Values, strings.TrimSpace(value))
+		}
+	} else if strings.HasPrefix(part, "!") && !strings.Contains(part, "=") {
+		requirement = LabelRequirement{Key: strings.TrimSpace(part[1:]), Operator: LabelNotExists}
+	} else if index := strings.Index(part, "!="); index >= 0 {
+		requirement = LabelRequirement{Key: part[:index], Operator: LabelNotEquals, Values: []string{part[index+2:]}}
+	} else if index := strings.Index(part, "=="); index >= 0 {
+		requirement = LabelRequirement{Key: part[:index], Operator: LabelEquals, Values: []string{part[index+2:]}}
+	} else if index := strings.Index(part, "="); index >= 0 {
+		requirement = LabelRequirement{Key: part[:index], Operator: LabelEquals, Values: []string{part[index+1:]}}
+	} else {
+		requirement = LabelRequirement{Key: part, Operator: LabelExists}
+	}
+
+	requirement.Key = strings.TrimSpace(requirement.Key)
+	if !labelKeyPattern.MatchString(requirement.Key) || len(requirement.Key) > maxLabelLength {
+		return requirement, errors.Errorf("invalid label key in requirement '%s'", part)
+	}
+	for i, value := range requirement.Values {
+		requirement.Values[i] = strings.TrimSpace(value)
+		if !labelValuePattern.MatchString(requirement.Values[i]) || len(requirement.Values[i]) > maxLabelLength {
+			return requirement, errors.Errorf("invalid label value in requirement '%s'", part)
+		}
+	}
+	return requirement, nil
+}
+
+// Matches returns true if labels satisfy all requirements of the selector
+func (selector LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range selector {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches returns true if labels satisfy the requirement. Like in Kubernetes, != and notin match missing labels.
+func (requirement LabelRequirement) Matches(labels map[string]string) bool {
+	value, exists := labels[requirement.Key]
+	switch requirement.Operator {
+	case LabelExists:
+		return exists
+	case LabelNotExists:
+		return !exists
+	case LabelEquals, LabelIn:
+		return exists && containsString(requirement.Values, value)
+	case LabelNotEquals, LabelNotIn:
+		return !exists || !containsString(requirement.Values, value)
+	}
+	return false
+}
+
+// Pairs returns label values of the requirement as "key=value" strings
+func (requirement LabelRequirement) Pairs() []string {
+	pairs := make([]string, 0, len(requirement.Values))
+	for _, value := range requirement.Values {
+		pairs = append(pairs, requirement.Key+"="+value)
+	}
+	return pairs
+}
+
+// LabelPairs returns labels as sorted "key=value" strings
+func LabelPairs(labels map[string]string) []string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func (schedule *Schedule) validateLabels() error {
+	for key, value := range schedule.Labels {
+		if !labelKeyPattern.MatchString(key) || len(key) > maxLabelLength {
+			return errors.Errorf("'labels' key '%s' is invalid", key)
+		}
+		if !labelValuePattern.MatchString(value) || len(value) > maxLabelLength {
+			return errors.Errorf("'labels' value '%s' of key '%s' is invalid", value, key)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
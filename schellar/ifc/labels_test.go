@@ -0,0 +1,87 @@
+package ifc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := ParseLabelSelector("team=netops, region in (eu, us),tier!=test,env==prod,owner,!deprecated,zone notin (a)")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	expected := LabelSelector{
+		{Key: "team", Operator: LabelEquals, Values: []string{"netops"}},
+		{Key: "region", Operator: LabelIn, Values: []string{"eu", "us"}},
+		{Key: "tier", Operator: LabelNotEquals, Values: []string{"test"}},
+		{Key: "env", Operator: LabelEquals, Values: []string{"prod"}},
+		{Key: "owner", Operator: LabelExists},
+		{Key: "deprecated", Operator: LabelNotExists},
+		{Key: "zone", Operator: LabelNotIn, Values: []string{"a"}},
+	}
+	if !reflect.DeepEqual(selector, expected) {
+		t.Fatalf("Unexpected selector %+v", selector)
+	}
+
+	empty, err := ParseLabelSelector(" ")
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("Unexpected empty selector %v. err=%v", empty, err)
+	}
+}
+
+func TestParseInvalidLabelSelector(t *testing.T) {
+	for _, selector := range []string{"team=netops,", "=netops", "te am=netops", "region in (eu,us", "a.b=c", "team=net ops", "!team=netops"} {
+		if _, err := ParseLabelSelector(selector); err == nil {
+			t.Errorf("Expected error parsing '%s'", selector)
+		}
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"team": "netops", "region": "eu"}
+	cases := map[string]bool{
+		"":                           true,
+		"team=netops":                true,
+		"team=netops,region=us":      false,
+		"region in (eu,us)":          true,
+		"region notin (eu,us)":       false,
+		"tier!=test":                 true,
+		"tier notin (test)":          true,
+		"team!=netops":               false,
+		"team":                       true,
+		"tier":                       false,
+		"!tier":                      true,
+		"!team":                      false,
+		"team=netops,region in (us)": false,
+	}
+	for selectorString, expected := range cases {
+		selector, err := ParseLabelSelector(selectorString)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing '%s'. err=%v", selectorString, err)
+		}
+		if selector.Matches(labels) != expected {
+			t.Errorf("Unexpected match of '%s', expected %v", selectorString, expected)
+		}
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	schedule := Schedule{Name: "backup", WorkflowName: "wf", CronString: "* * * * *",
+		Labels: map[string]string{"team": "netops", "device-group": "core_routers", "version": "1.2"}}
+	if err := schedule.ValidateAndUpdate(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	for _, labels := range []map[string]string{{"": "x"}, {"a.b": "x"}, {"team": "net ops"}, {"$team": "x"}} {
+		schedule.Labels = labels
+		if schedule.ValidateAndUpdate() == nil {
+			t.Errorf("Expected error validating labels %v", labels)
+		}
+	}
+}
+
+func TestLabelPairs(t *testing.T) {
+	pairs := LabelPairs(map[string]string{"team": "netops", "region": "eu"})
+	if !reflect.DeepEqual(pairs, []string{"region=eu", "team=netops"}) {
+		t.Errorf("Unexpected pairs %v", pairs)
+	}
+}
@@ -0,0 +1,172 @@
+package ifc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxContextBytes limits size of JSON encoded workflow context updated from workflow output
+const DefaultMaxContextBytes = 64 * 1024
+
+// ErrContextTooLarge is returned when workflow output would grow the workflow context over its limit
+var ErrContextTooLarge = errors.New("workflow context too large")
+
+// Context version sources tell what replaced the versioned workflow context
+const (
+	ContextSourceOutput  = "WORKFLOW_OUTPUT"
+	ContextSourceUpdate  = "UPDATE"
+	ContextSourceRestore = "RESTORE"
+)
+
+// ContextVersion is a workflow context of a schedule replaced by a newer one
+type ContextVersion struct {
+	ScheduleName    string                 `json:"scheduleName" bson:"scheduleName"`
+	Version         int                    `json:"version" bson:"version"`
+	WorkflowContext map[string]interface{} `json:"workflowContext" bson:"workflowContext"`
+	// Source of the change that replaced this context
+	Source string `json:"source" bson:"source"`
+	// WorkflowID of the workflow whose output replaced this context
+	WorkflowID string    `json:"workflowId,omitempty" bson:"workflowId"`
+	Created    time.Time `json:"created" bson:"created"`
+}
+
+// ContextLimit returns maximum size of JSON encoded workflow context updated from workflow output
+func (schedule *Schedule) ContextLimit() int {
+	if schedule.MaxContextBytes <= 0 {
+		return DefaultMaxContextBytes
+	}
+	return schedule.MaxContextBytes
+}
+
+// MergeOutput returns a copy of the workflow context updated with output of a finished workflow.
+// Without outputMapping the whole output is stored under lastExecution key, otherwise only the selected
+// output values are stored under their context keys. Values missing in the output leave the context key unchanged.
+func (schedule *Schedule) MergeOutput(output map[string]interface{}) (map[string]interface{}, error) {
+	context := make(map[string]interface{}, len(schedule.WorkflowContext)+1)
+	for key, value := range schedule.WorkflowContext {
+		context[key] = value
+	}
+	if len(schedule.OutputMapping) == 0 {
+		context["lastExecution"] = output
+	} else {
+		for key, path := range schedule.OutputMapping {
+			value, found, err := SelectPath(output, path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "'outputMapping' of %s is invalid", key)
+			}
+			if found {
+				context[key] = value
+			}
+		}
+	}
+	data, err := json.Marshal(context)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > schedule.ContextLimit() {
+		return nil, fmt.Errorf("%w: %d bytes exceed maxContextBytes %d", ErrContextTooLarge, len(data), schedule.ContextLimit())
+	}
+	return context, nil
+}
+
+func (schedule *Schedule) validateOutputMapping() error {
+	if schedule.MaxContextBytes < 0 {
+		return errors.New("'maxContextBytes' cannot be negative")
+	}
+	for key, path := range schedule.OutputMapping {
+		if key == "" || key == "scheduleName" {
+			return errors.Errorf("'outputMapping' key '%s' is not allowed", key)
+		}
+		_, err := parsePath(path)
+		if err != nil {
+			return errors.Wrapf(err, "'outputMapping' of %s is invalid", key)
+		}
+	}
+	return nil
+}
+
+// SelectPath returns value at JSONPath like path in the document, e.g. $.result.items[0].id or $['key with dots'].
+// Returns false if the value does not exist.
+func SelectPath(document interface{}, path string) (interface{}, bool, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	value := document
+	for _, segment := range segments {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			if segment.isIndex {
+				return nil, false, nil
+			}
+			item, exists := typed[segment.key]
+			if !exists {
+				return nil, false, nil
+			}
+			value = item
+		case []interface{}:
+			if !segment.isIndex || segment.index >= len(typed) {
+				return nil, false, nil
+			}
+			value = typed[segment.index]
+		default:
+			return nil, false, nil
+		}
+	}
+	return value, true, nil
+}
+
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.Errorf("path %s has to start with $", path)
+	}
+	segments := make([]pathSegment, 0)
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, errors.Errorf("unclosed bracket in path %s", path)
+			}
+			segments = append(segments, pathSegment{key: rest[2:end]})
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, errors.Errorf("unclosed bracket in path %s", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, errors.Errorf("invalid index %s in path %s", rest[1:end], path)
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, errors.Errorf("empty key in path %s", path)
+			}
+			segments = append(segments, pathSegment{key: key})
+			rest = rest[end+1:]
+		default:
+			return nil, errors.Errorf("unexpected '%s' in path %s", rest, path)
+		}
+	}
+	return segments, nil
+}
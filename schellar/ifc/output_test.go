@@ -0,0 +1,115 @@
+package ifc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testOutput() map[string]interface{} {
+	return map[string]interface{}{
+		"result": map[string]interface{}{
+			"items":    []interface{}{map[string]interface{}{"id": "first"}, map[string]interface{}{"id": "second"}},
+			"last.day": "2023-05-01",
+		},
+		"count": float64(2),
+	}
+}
+
+func TestSelectPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{"$", testOutput(), true},
+		{"$.count", float64(2), true},
+		{"$.result.items[1].id", "second", true},
+		{"$['result']['last.day']", "2023-05-01", true},
+		{"$.result.items[2].id", nil, false},
+		{"$.result.missing", nil, false},
+		{"$.count.value", nil, false},
+		{"$.result[0]", nil, false},
+	}
+	for _, test := range tests {
+		value, found, err := SelectPath(testOutput(), test.path)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.path, err)
+		}
+		if found != test.found || !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("Unexpected value for %s: %v (found %v)", test.path, value, found)
+		}
+	}
+}
+
+func TestSelectPathInvalid(t *testing.T) {
+	for _, path := range []string{"", "count", "$.", "$..count", "$.items[x]", "$.items[-1]", "$['count'", "$count"} {
+		_, _, err := SelectPath(testOutput(), path)
+		if err == nil {
+			t.Errorf("Expected error for path '%s'", path)
+		}
+	}
+}
+
+func TestMergeOutputWithoutMapping(t *testing.T) {
+	schedule := Schedule{WorkflowContext: map[string]interface{}{"user": "value"}}
+	context, err := schedule.MergeOutput(testOutput())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{"user": "value", "lastExecution": testOutput()}
+	if !reflect.DeepEqual(context, expected) {
+		t.Errorf("Unexpected context %v", context)
+	}
+	if _, exists := schedule.WorkflowContext["lastExecution"]; exists {
+		t.Errorf("Workflow context of the schedule must not be modified")
+	}
+}
+
+func TestMergeOutputWithMapping(t *testing.T) {
+	schedule := Schedule{
+		WorkflowContext: map[string]interface{}{"user": "value", "since": "2023-04-30"},
+		OutputMapping: map[string]string{
+			"since":   "$.result['last.day']",
+			"firstId": "$.result.items[0].id",
+			"missing": "$.result.missing",
+		},
+	}
+	context, err := schedule.MergeOutput(testOutput())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{"user": "value", "since": "2023-05-01", "firstId": "first"}
+	if !reflect.DeepEqual(context, expected) {
+		t.Errorf("Unexpected context %v", context)
+	}
+}
+
+func TestMergeOutputTooLarge(t *testing.T) {
+	schedule := Schedule{WorkflowContext: map[string]interface{}{}, MaxContextBytes: 20}
+	_, err := schedule.MergeOutput(testOutput())
+	if !errors.Is(err, ErrContextTooLarge) {
+		t.Errorf("Expected ErrContextTooLarge, got %v", err)
+	}
+	schedule.MaxContextBytes = 0
+	if schedule.ContextLimit() != DefaultMaxContextBytes {
+		t.Errorf("Unexpected default limit %d", schedule.ContextLimit())
+	}
+}
+
+func TestValidateOutputMapping(t *testing.T) {
+	valid := Schedule{OutputMapping: map[string]string{"since": "$.result.items[0].id"}}
+	if err := valid.validateOutputMapping(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	invalid := []Schedule{
+		{OutputMapping: map[string]string{"since": "result"}},
+		{OutputMapping: map[string]string{"scheduleName": "$.name"}},
+		{MaxContextBytes: -1},
+	}
+	for _, schedule := range invalid {
+		if err := schedule.validateOutputMapping(); err == nil {
+			t.Errorf("Expected error for %v", schedule)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ifc
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Pause disables the schedule, recording who paused it, why and optionally until when.
+// A paused schedule may be paused again to change its reason or end.
+func (schedule *Schedule) Pause(by string, reason string, until *time.Time, now time.Time) error {
+	if until != nil && !until.After(now) {
+		return errors.Errorf("'until' %s has to be in the future", until.Format(time.RFC3339))
+	}
+	if !schedule.IsPaused() {
+		schedule.PausedAt = &now
+	}
+	schedule.Enabled = false
+	schedule.PausedBy = by
+	schedule.PauseReason = reason
+	schedule.PausedUntil = until
+	return nil
+}
+
+// Resume enables the paused schedule and clears its pause
+func (schedule *Schedule) Resume(now time.Time) {
+	schedule.SetEnabled(true, now)
+	schedule.clearPause()
+}
+
+// SetEnabled enables or disables the schedule. Enabling a disabled schedule records now as EnabledAt,
+// so that timer triggers missed while it was off are not caught up.
+func (schedule *Schedule) SetEnabled(enabled bool, now time.Time) {
+	if enabled && !schedule.Enabled {
+		schedule.EnabledAt = &now
+	}
+	schedule.Enabled = enabled
+}
+
+// MisfireSince returns the time after which missed timer triggers of the schedule are caught up, the later
+// of its last fire time and the time it was enabled. Nil if the schedule has never fired.
+func (schedule *Schedule) MisfireSince() *time.Time {
+	if schedule.LastFireTime == nil {
+		return nil
+	}
+	if schedule.EnabledAt != nil && schedule.EnabledAt.After(*schedule.LastFireTime) {
+		return schedule.EnabledAt
+	}
+	return schedule.LastFireTime
+}
+
+// IsPaused returns true if the schedule was disabled by Pause
+func (schedule *Schedule) IsPaused() bool {
+	return !schedule.Enabled && schedule.PausedAt != nil
+}
+
+// PauseExpired returns true if the schedule is paused until a time that is not after now
+func (schedule *Schedule) PauseExpired(now time.Time) bool {
+	return schedule.IsPaused() && schedule.PausedUntil != nil && !schedule.PausedUntil.After(now)
+}
+
+func (schedule *Schedule) clearPause() {
+	schedule.PausedBy = ""
+	schedule.PauseReason = ""
+	schedule.PausedAt = nil
+	schedule.PausedUntil = nil
+}
@@ -0,0 +1,95 @@
+package ifc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPauseAndResume(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	until := now.Add(2 * time.Hour)
+	schedule := Schedule{Name: "backup", Enabled: true}
+	err := schedule.Pause("alice", "incident 42", &until, now)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if schedule.Enabled || !schedule.IsPaused() || schedule.PausedBy != "alice" || schedule.PauseReason != "incident 42" ||
+		!schedule.PausedAt.Equal(now) || !schedule.PausedUntil.Equal(until) {
+		t.Fatalf("Unexpected paused schedule %+v", schedule)
+	}
+	if schedule.PauseExpired(now) || !schedule.PauseExpired(until) {
+		t.Errorf("Unexpected pause expiration")
+	}
+
+	// pausing again keeps the original pause time
+	err = schedule.Pause("bob", "extended", nil, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if schedule.PausedBy != "bob" || !schedule.PausedAt.Equal(now) || schedule.PausedUntil != nil {
+		t.Fatalf("Unexpected paused schedule %+v", schedule)
+	}
+	if schedule.PauseExpired(now.Add(24 * time.Hour)) {
+		t.Errorf("Pause without end cannot expire")
+	}
+
+	schedule.Resume(now.Add(2 * time.Hour))
+	if !schedule.EnabledAt.Equal(now.Add(2*time.Hour)) || !schedule.Enabled || schedule.IsPaused() || schedule.PausedBy != "" || schedule.PauseReason != "" ||
+		schedule.PausedAt != nil || schedule.PausedUntil != nil {
+		t.Fatalf("Unexpected resumed schedule %+v", schedule)
+	}
+}
+
+func TestMisfireSince(t *testing.T) {
+	lastFireTime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	schedule := Schedule{Name: "backup", Enabled: true}
+	if schedule.MisfireSince() != nil {
+		t.Errorf("Schedule that never fired has no missed triggers")
+	}
+	schedule.LastFireTime = &lastFireTime
+	if !schedule.MisfireSince().Equal(lastFireTime) {
+		t.Errorf("Unexpected misfire since %v", schedule.MisfireSince())
+	}
+
+	// enabling an enabled schedule keeps missed triggers
+	schedule.SetEnabled(true, lastFireTime.Add(time.Hour))
+	if schedule.EnabledAt != nil {
+		t.Errorf("Unexpected enabledAt of schedule that was not disabled %v", schedule.EnabledAt)
+	}
+	schedule.SetEnabled(false, lastFireTime.Add(time.Hour))
+	enabledAt := lastFireTime.Add(7 * 24 * time.Hour)
+	schedule.SetEnabled(true, enabledAt)
+	if !schedule.MisfireSince().Equal(enabledAt) {
+		t.Errorf("Expected triggers missed while disabled to be ignored, misfire since %v", schedule.MisfireSince())
+	}
+	later := enabledAt.Add(time.Hour)
+	schedule.LastFireTime = &later
+	if !schedule.MisfireSince().Equal(later) {
+		t.Errorf("Unexpected misfire since %v", schedule.MisfireSince())
+	}
+}
+
+func TestPauseUntilPast(t *testing.T) {
+	now := time.Now()
+	schedule := Schedule{Name: "backup", Enabled: true}
+	if schedule.Pause("alice", "", &now, now) == nil {
+		t.Errorf("Expected error pausing until now")
+	}
+	if !schedule.Enabled {
+		t.Errorf("Schedule cannot be paused by invalid pause")
+	}
+}
+
+func TestEnabledScheduleIsNotPaused(t *testing.T) {
+	now := time.Now()
+	schedule := Schedule{Name: "backup", WorkflowName: "wf", CronString: "* * * * *", Enabled: false}
+	schedule.Pause("alice", "maintenance", nil, now)
+	schedule.Enabled = true
+	err := schedule.ValidateAndUpdate()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if schedule.IsPaused() || schedule.PausedAt != nil || schedule.PausedBy != "" {
+		t.Errorf("Pause of enabled schedule was not cleared %+v", schedule)
+	}
+}
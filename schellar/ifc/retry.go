@@ -0,0 +1,76 @@
+package ifc
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Defaults applied to retry policy of schedules that retry failed workflow launches
+const (
+	DefaultRetryInitialDelaySeconds = 10
+	DefaultRetryMultiplier          = 2.0
+	DefaultRetryMaxDelaySeconds     = 300
+)
+
+// RetryPolicy decides how many times and how often a failed workflow launch is attempted again
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt, 0 or 1 means failed launches are not retried
+	MaxAttempts         int     `json:"maxAttempts,omitempty" bson:"maxAttempts"`
+	InitialDelaySeconds int     `json:"initialDelaySeconds,omitempty" bson:"initialDelaySeconds"`
+	Multiplier          float64 `json:"multiplier,omitempty" bson:"multiplier"`
+	MaxDelaySeconds     int     `json:"maxDelaySeconds,omitempty" bson:"maxDelaySeconds"`
+}
+
+func (policy *RetryPolicy) validateAndUpdate() error {
+	if policy.MaxAttempts < 0 {
+		return errors.New("'retryPolicy.maxAttempts' cannot be negative")
+	}
+	if policy.InitialDelaySeconds < 0 {
+		return errors.New("'retryPolicy.initialDelaySeconds' cannot be negative")
+	}
+	if policy.MaxDelaySeconds < 0 {
+		return errors.New("'retryPolicy.maxDelaySeconds' cannot be negative")
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		return errors.New("'retryPolicy.multiplier' cannot be less than 1")
+	}
+	if policy.MaxAttempts <= 1 {
+		return nil
+	}
+	if policy.InitialDelaySeconds == 0 {
+		policy.InitialDelaySeconds = DefaultRetryInitialDelaySeconds
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = DefaultRetryMultiplier
+	}
+	if policy.MaxDelaySeconds == 0 {
+		policy.MaxDelaySeconds = DefaultRetryMaxDelaySeconds
+	}
+	if policy.MaxDelaySeconds < policy.InitialDelaySeconds {
+		return errors.New("'retryPolicy.maxDelaySeconds' cannot be less than 'retryPolicy.initialDelaySeconds'")
+	}
+	return nil
+}
+
+// Attempts returns how many times a workflow launch is attempted in total
+func (policy RetryPolicy) Attempts() int {
+	if policy.MaxAttempts < 1 {
+		return 1
+	}
+	return policy.MaxAttempts
+}
+
+// Delay returns how long to wait after the failed attempt (counted from 1) before the next one
+func (policy RetryPolicy) Delay(attempt int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(policy.InitialDelaySeconds) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxDelaySeconds > 0 && delay > float64(policy.MaxDelaySeconds) {
+		delay = float64(policy.MaxDelaySeconds)
+	}
+	return time.Duration(delay * float64(time.Second))
+}
@@ -0,0 +1,42 @@
+package ifc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 6}
+	err := policy.validateAndUpdate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second,
+		80 * time.Second, 160 * time.Second, 300 * time.Second}
+	for i, delay := range expected {
+		if actual := policy.Delay(i + 1); actual != delay {
+			t.Fatalf("Unexpected delay after attempt %d: %s != %s", i+1, actual, delay)
+		}
+	}
+}
+
+func TestRetryPolicyValidation(t *testing.T) {
+	invalid := []RetryPolicy{
+		{MaxAttempts: -1},
+		{MaxAttempts: 3, Multiplier: 0.5},
+		{MaxAttempts: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 30},
+	}
+	for _, policy := range invalid {
+		if err := policy.validateAndUpdate(); err == nil {
+			t.Fatalf("Expected error for %+v", policy)
+		}
+	}
+
+	noRetry := RetryPolicy{}
+	if err := noRetry.validateAndUpdate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if noRetry.Attempts() != 1 || noRetry.InitialDelaySeconds != 0 {
+		t.Fatalf("Unexpected policy without retries: %+v", noRetry)
+	}
+}
@@ -0,0 +1,134 @@
+package ifc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig"
+)
+
+// TemplateEnvPrefix limits environment variables readable by templates with env function,
+// so that credentials from schellar configuration cannot be passed to workflows
+const TemplateEnvPrefix = "SCHELLAR_TEMPLATE_ENV_"
+
+// templateFuncs are sprig functions with env restricted to TemplateEnvPrefix variables and without expandenv
+var templateFuncs = func() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	delete(funcs, "expandenv")
+	funcs["env"] = func(name string) string {
+		if !strings.HasPrefix(name, TemplateEnvPrefix) {
+			return ""
+		}
+		return os.Getenv(name)
+	}
+	return funcs
+}()
+
+// TemplateData is available to templates in workflow context values, e.g. {{ .FireTime | date "2006-01-02" }}
+type TemplateData struct {
+	ScheduleName string
+	Trigger      string
+	// FireTime is the time the schedule was triggered for, in time zone of the schedule
+	FireTime time.Time
+	// PreviousFireTime is fire time of the previous execution of the schedule, zero time for the first one
+	PreviousFireTime time.Time
+	// RunNumber counts executions of the schedule by fire time, starting from 1
+	RunNumber int
+	// LastExecution is output of the last finished workflow of the schedule
+	LastExecution map[string]interface{}
+}
+
+// RenderContext returns a copy of the workflow context with templates in string values (including nested ones) rendered.
+// Output of the last execution stored in the context is copied as it is.
+func RenderContext(context map[string]interface{}, data TemplateData) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(context))
+	for key, value := range context {
+		if key == "lastExecution" {
+			result[key] = value
+			continue
+		}
+		rendered, err := renderValue(value, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		result[key] = rendered
+	}
+	return result, nil
+}
+
+// ValidateContextTemplates checks syntax of templates in workflow context values
+func ValidateContextTemplates(context map[string]interface{}) error {
+	for key, value := range context {
+		if key == "lastExecution" {
+			continue
+		}
+		_, err := walkValue(value, func(text string) (string, error) {
+			_, err := parseTemplate(text)
+			return text, err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("context").Funcs(templateFuncs).Parse(text)
+}
+
+func renderValue(value interface{}, data TemplateData) (interface{}, error) {
+	return walkValue(value, func(text string) (string, error) {
+		return renderString(text, data)
+	})
+}
+
+// walkValue copies the value with its string values (including nested ones) replaced by transform
+func walkValue(value interface{}, transform func(string) (string, error)) (interface{}, error) {
+	switch typed := value.(type) {
+	case string:
+		return transform(typed)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			rendered, err := walkValue(item, transform)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			result[key] = rendered
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			rendered, err := walkValue(item, transform)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			result[i] = rendered
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+func renderString(text string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, data)
+	if err != nil {
+		return "", err
+	}
+	// missing values (e.g. LastExecution keys of the first run) render as empty strings
+	return strings.ReplaceAll(out.String(), "<no value>", ""), nil
+}
@@ -0,0 +1,91 @@
+package ifc
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenderContext(t *testing.T) {
+	os.Setenv("SCHELLAR_TEMPLATE_ENV_REGION", "eu")
+	defer os.Unsetenv("SCHELLAR_TEMPLATE_ENV_REGION")
+	context := map[string]interface{}{
+		"day":     `{{ .FireTime | date "2006-01-02" }}`,
+		"from":    `{{ .PreviousFireTime | date "15:04" }}`,
+		"run":     "run-{{ .RunNumber }}",
+		"since":   `{{ .LastExecution.lastDate | default "none" }}`,
+		"missing": "{{ .LastExecution.other }}",
+		"region":  `{{ env "SCHELLAR_TEMPLATE_ENV_REGION" }}`,
+		"plain":   "no template",
+		"number":  float64(5),
+		"nested":  map[string]interface{}{"list": []interface{}{"{{ .ScheduleName }}", true}},
+		"lastExecution": map[string]interface{}{
+			"lastDate": "{{ not rendered }}",
+		},
+	}
+	data := TemplateData{
+		ScheduleName:     "schedule",
+		FireTime:         time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		PreviousFireTime: time.Date(2023, 4, 30, 12, 30, 0, 0, time.UTC),
+		RunNumber:        3,
+		LastExecution:    map[string]interface{}{},
+	}
+	rendered, err := RenderContext(context, data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"day":           "2023-05-01",
+		"from":          "12:30",
+		"run":           "run-3",
+		"since":         "none",
+		"missing":       "",
+		"region":        "eu",
+		"plain":         "no template",
+		"number":        float64(5),
+		"nested":        map[string]interface{}{"list": []interface{}{"schedule", true}},
+		"lastExecution": context["lastExecution"],
+	}
+	if !reflect.DeepEqual(expected, rendered) {
+		t.Fatalf("Unexpected rendered context\n%v\n%v", expected, rendered)
+	}
+	if context["run"] != "run-{{ .RunNumber }}" {
+		t.Fatalf("Stored context was modified")
+	}
+}
+
+func TestValidateContextTemplates(t *testing.T) {
+	valid := map[string]interface{}{"day": `{{ .FireTime | date "2006-01-02" }}`}
+	if err := ValidateContextTemplates(valid); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	invalid := map[string]interface{}{"nested": map[string]interface{}{"day": "{{ .FireTime "}}
+	if err := ValidateContextTemplates(invalid); err == nil {
+		t.Fatalf("Expected error for unclosed template")
+	}
+}
+
+func TestRenderContextEnvRestricted(t *testing.T) {
+	t.Setenv("SCHELLAR_TEMPLATE_ENV_REGION", "eu")
+	t.Setenv("POSTGRES_PASSWORD", "secret")
+	context := map[string]interface{}{
+		"region":   `{{ env "SCHELLAR_TEMPLATE_ENV_REGION" }}`,
+		"password": `{{ env "POSTGRES_PASSWORD" }}`,
+	}
+	rendered, err := RenderContext(context, TemplateData{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rendered["region"] != "eu" || rendered["password"] != "" {
+		t.Fatalf("Expected only variables with %s prefix, got %v", TemplateEnvPrefix, rendered)
+	}
+
+	expand := map[string]interface{}{"password": "{{ expandenv \"$POSTGRES_PASSWORD\" }}"}
+	if err := ValidateContextTemplates(expand); err == nil {
+		t.Fatalf("Expected error for expandenv function")
+	}
+	if _, err := RenderContext(expand, TemplateData{}); err == nil {
+		t.Fatalf("Expected error rendering expandenv function")
+	}
+}
@@ -26,6 +26,37 @@ func makeSchedule(now time.Time) ifc.Schedule {
 		LastUpdate:          now,
 		CorrelationID:       "CorrelationID",
 		TaskToDomain:        nil,
+		MisfirePolicy:       "SKIP",
+		MisfireMaxCount:     0,
+		LastFireTime:        nil,
+		TimeZone:            "Europe/Bratislava",
+		CronFormat:          "STANDARD",
+		Condition:           "OK",
+		ConditionMessage:    "",
+		MaxRunDuration:      7200,
+		OnOverrun:           "TERMINATE",
+		RetryPolicy: ifc.RetryPolicy{
+			MaxAttempts:         3,
+			InitialDelaySeconds: 5,
+			Multiplier:          1.5,
+			MaxDelaySeconds:     60,
+		},
+		OnFailure:         "RELAUNCH",
+		OnFailureMaxCount: 2,
+		ConcurrencyPolicy: "QUEUE",
+		MaxConcurrentRuns: 2,
+		TriggerMode:       "CRON",
+		DependsOn: []ifc.Dependency{
+			{ScheduleName: "Upstream", Condition: "COMPLETED", WithinMinutes: 60},
+		},
+		OutputMapping:          map[string]string{"since": "$.result.lastDate"},
+		UpdateContextOnFailure: true,
+		MaxContextBytes:        1024,
+		IncludeCalendars:       []string{"business-hours"},
+		ExcludeCalendars:       []string{"freeze", "holidays"},
+		JitterSeconds:          30,
+		JitterMode:             "HASH_OFFSET",
+		Labels:                 map[string]string{"team": "netops", "region": "eu"},
 	}
 }
 
@@ -48,6 +79,51 @@ func AllIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
 	t.Run("UpdateIntegration", func(t *testing.T) {
 		UpdateIntegration(t, dbGetter)
 	})
+	t.Run("UpdateLastFireTimeIntegration", func(t *testing.T) {
+		UpdateLastFireTimeIntegration(t, dbGetter)
+	})
+	t.Run("UpdateConditionIntegration", func(t *testing.T) {
+		UpdateConditionIntegration(t, dbGetter)
+	})
+	t.Run("ExecutionIntegration", func(t *testing.T) {
+		ExecutionIntegration(t, dbGetter)
+	})
+	t.Run("LeaseIntegration", func(t *testing.T) {
+		LeaseIntegration(t, dbGetter)
+	})
+	t.Run("ContextVersionIntegration", func(t *testing.T) {
+		ContextVersionIntegration(t, dbGetter)
+	})
+	t.Run("CalendarIntegration", func(t *testing.T) {
+		CalendarIntegration(t, dbGetter)
+	})
+	t.Run("PauseIntegration", func(t *testing.T) {
+		PauseIntegration(t, dbGetter)
+	})
+	t.Run("FindSchedulesIntegration", func(t *testing.T) {
+		FindSchedulesIntegration(t, dbGetter)
+	})
+	t.Run("UpdateNextFireTimeIntegration", func(t *testing.T) {
+		UpdateNextFireTimeIntegration(t, dbGetter)
+	})
+	t.Run("FilterSchedulesIntegration", func(t *testing.T) {
+		FilterSchedulesIntegration(t, dbGetter)
+	})
+}
+
+func makeExecution(id string, fireTime time.Time) ifc.Execution {
+	return ifc.Execution{
+		ID:           id,
+		ScheduleName: "Name",
+		FireTime:     fireTime,
+		Trigger:      "TIMER",
+		WorkflowID:   "workflow-" + id,
+		Status:       "RUNNING",
+		StartTime:    &fireTime,
+		Attempts:     1,
+		Recoveries:   0,
+		WorkflowName: "Workflow",
+	}
 }
 
 func assertEquals(t *testing.T, expected ifc.Schedule, actual ifc.Schedule, hint string) {
@@ -212,3 +288,480 @@ func UpdateIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
 	// check equality
 	assertEquals(t, schedule, actual, "Inserted != selected")
 }
+
+func UpdateLastFireTimeIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	schedule := makeSchedule(now)
+	err := db.Insert(schedule)
+	if err != nil {
+		t.Fatalf("Cannot insert: %v", err)
+	}
+	defer db.RemoveByName(schedule.Name)
+
+	schedule.LastFireTime = &now
+	err = db.UpdateLastFireTime(schedule.Name, now)
+	if err != nil {
+		t.Fatalf("Cannot update: %v", err)
+	}
+	schedules := ExpectTableSize(db, 1, "after insert", t)
+	actual := schedules[0]
+	// selected WorkflowContext is never null
+	schedule.WorkflowContext = make(map[string]interface{})
+	// check equality
+	assertEquals(t, schedule, actual, "Inserted != selected")
+}
+
+func UpdateConditionIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	schedule := makeSchedule(now)
+	err := db.Insert(schedule)
+	if err != nil {
+		t.Fatalf("Cannot insert: %v", err)
+	}
+	defer db.RemoveByName(schedule.Name)
+
+	schedule.Condition = "WARNING"
+	schedule.ConditionMessage = "ConditionMessage"
+	err = db.UpdateCondition(schedule.Name, schedule.Condition, schedule.ConditionMessage)
+	if err != nil {
+		t.Fatalf("Cannot update: %v", err)
+	}
+	schedules := ExpectTableSize(db, 1, "after insert", t)
+	actual := schedules[0]
+	// selected WorkflowContext is never null
+	schedule.WorkflowContext = make(map[string]interface{})
+	// check equality
+	assertEquals(t, schedule, actual, "Inserted != selected")
+}
+
+func ExecutionIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	schedule := makeSchedule(now)
+	err := db.Insert(schedule)
+	if err != nil {
+		t.Fatalf("Cannot insert: %v", err)
+	}
+	defer db.RemoveByName(schedule.Name)
+
+	for i := 1; i <= 3; i++ {
+		execution := makeExecution(fmt.Sprintf("%d", i), now.Add(time.Duration(i)*time.Minute))
+		err = db.InsertExecution(execution)
+		if err != nil {
+			t.Fatalf("Cannot insert execution: %v", err)
+		}
+	}
+
+	found, err := db.FindExecutionByWorkflowID("workflow-2")
+	if err != nil || found == nil {
+		t.Fatalf("Cannot FindExecutionByWorkflowID: %v", err)
+	}
+	found.Status = "COMPLETED"
+	found.EndTime = &now
+	err = db.UpdateExecution(*found)
+	if err != nil {
+		t.Fatalf("Cannot update execution: %v", err)
+	}
+
+	count, err := db.CountExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name, Status: "RUNNING"})
+	if err != nil || count != 2 {
+		t.Fatalf("Unexpected CountExecutions. Err=%v. Count=%d", err, count)
+	}
+
+	firstPage, err := db.FindExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name}, "", 2)
+	if err != nil || len(firstPage) != 2 || firstPage[0].ID != "3" || firstPage[1].ID != "2" {
+		t.Fatalf("Unexpected first page. Err=%v. Page=%v", err, firstPage)
+	}
+	if firstPage[1].Status != "COMPLETED" || firstPage[1].EndTime == nil || !firstPage[1].EndTime.Equal(now) {
+		t.Fatalf("Unexpected updated execution %v", firstPage[1])
+	}
+	all, err := db.FindExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name}, "", 0)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("Unexpected unlimited page. Err=%v. Page=%v", err, all)
+	}
+	secondPage, err := db.FindExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name}, "2", 2)
+	if err != nil || len(secondPage) != 1 || secondPage[0].ID != "1" {
+		t.Fatalf("Unexpected second page. Err=%v. Page=%v", err, secondPage)
+	}
+
+	firedBefore := firstPage[1].FireTime
+	earlier, err := db.FindExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name, FiredBefore: &firedBefore}, "", 0)
+	if err != nil || len(earlier) != 1 || earlier[0].ID != "1" {
+		t.Fatalf("Unexpected executions fired before %s. Err=%v. Page=%v", firedBefore, err, earlier)
+	}
+	count, err = db.CountExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name, ExcludeStatus: "COMPLETED"})
+	if err != nil || count != 2 {
+		t.Fatalf("Unexpected CountExecutions excluding status. Err=%v. Count=%d", err, count)
+	}
+	count, err = db.CountExecutions(ifc.ExecutionFilter{WorkflowName: "Workflow", Status: "RUNNING"})
+	if err != nil || count != 2 {
+		t.Fatalf("Unexpected CountExecutions by workflow name. Err=%v. Count=%d", err, count)
+	}
+	count, err = db.CountExecutions(ifc.ExecutionFilter{WorkflowName: "Other"})
+	if err != nil || count != 0 {
+		t.Fatalf("Unexpected CountExecutions of other workflow. Err=%v. Count=%d", err, count)
+	}
+
+	err = db.RemoveByName(schedule.Name)
+	if err != nil {
+		t.Fatalf("Cannot remove: %v", err)
+	}
+	count, err = db.CountExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name})
+	if err != nil || count != 0 {
+		t.Fatalf("Executions not removed with schedule. Err=%v. Count=%d", err, count)
+	}
+}
+
+func expectLease(t *testing.T, db ifc.DB, holder string, ttl time.Duration, expected bool) {
+	acquired, err := db.AcquireLease("test-lease", holder, ttl)
+	if err != nil {
+		t.Fatalf("Cannot acquire lease: %v", err)
+	}
+	if acquired != expected {
+		t.Fatalf("Unexpected lease state for %s. Acquired=%v", holder, acquired)
+	}
+}
+
+func LeaseIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	defer db.ReleaseLease("test-lease", "a")
+	defer db.ReleaseLease("test-lease", "b")
+
+	expectLease(t, db, "a", time.Minute, true)
+	expectLease(t, db, "b", time.Minute, false)
+	// renewal
+	expectLease(t, db, "a", time.Millisecond, true)
+	time.Sleep(10 * time.Millisecond)
+	// expired lease is taken over
+	expectLease(t, db, "b", time.Minute, true)
+	expectLease(t, db, "a", time.Minute, false)
+
+	err := db.ReleaseLease("test-lease", "b")
+	if err != nil {
+		t.Fatalf("Cannot release lease: %v", err)
+	}
+	expectLease(t, db, "a", time.Minute, true)
+}
+
+func ContextVersionIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	schedule := makeSchedule(now)
+	err := db.Insert(schedule)
+	if err != nil {
+		t.Fatalf("Cannot insert: %v", err)
+	}
+	defer db.RemoveByName(schedule.Name)
+
+	for i := 1; i <= 4; i++ {
+		err = db.InsertContextVersion(ifc.ContextVersion{
+			ScheduleName:    schedule.Name,
+			WorkflowContext: map[string]interface{}{"run": fmt.Sprintf("%d", i)},
+			Source:          ifc.ContextSourceOutput,
+			WorkflowID:      fmt.Sprintf("workflow-%d", i),
+			Created:         now,
+		}, 3)
+		if err != nil {
+			t.Fatalf("Cannot insert context version: %v", err)
+		}
+	}
+
+	versions, err := db.FindContextVersions(schedule.Name, 0)
+	if err != nil || len(versions) != 3 || versions[0].Version != 4 || versions[2].Version != 2 {
+		t.Fatalf("Unexpected context versions. Err=%v. Versions=%v", err, versions)
+	}
+	if versions[0].WorkflowContext["run"] != "4" || versions[0].WorkflowID != "workflow-4" || !versions[0].Created.Equal(now) {
+		t.Fatalf("Unexpected latest context version %v", versions[0])
+	}
+	limited, err := db.FindContextVersions(schedule.Name, 1)
+	if err != nil || len(limited) != 1 || limited[0].Version != 4 {
+		t.Fatalf("Unexpected limited context versions. Err=%v. Versions=%v", err, limited)
+	}
+
+	version, err := db.FindContextVersion(schedule.Name, 3)
+	if err != nil || version == nil || version.WorkflowContext["run"] != "3" {
+		t.Fatalf("Unexpected context version 3. Err=%v. Version=%v", err, version)
+	}
+	version, err = db.FindContextVersion(schedule.Name, 1)
+	if err != nil || version != nil {
+		t.Fatalf("Pruned context version found. Err=%v. Version=%v", err, version)
+	}
+
+	err = db.RemoveByName(schedule.Name)
+	if err != nil {
+		t.Fatalf("Cannot remove: %v", err)
+	}
+	versions, err = db.FindContextVersions(schedule.Name, 0)
+	if err != nil || len(versions) != 0 {
+		t.Fatalf("Context versions not removed with schedule. Err=%v. Versions=%v", err, versions)
+	}
+}
+
+func CalendarIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	calendar := ifc.Calendar{
+		Name:        "freeze",
+		Description: "maintenance freeze",
+		TimeZone:    "Europe/Bratislava",
+		DateRanges: []ifc.DateRange{
+			{From: now, To: now.Add(24 * time.Hour), Description: "freeze"},
+		},
+		WeeklyWindows: []ifc.WeeklyWindow{
+			{Days: []string{"SATURDAY", "SUNDAY"}, StartTime: "22:00", EndTime: "02:00"},
+		},
+		LastUpdate: now,
+	}
+	err := db.InsertCalendar(calendar)
+	if err != nil {
+		t.Fatalf("Cannot insert calendar: %v", err)
+	}
+	defer db.RemoveCalendarByName(calendar.Name)
+
+	found, err := db.FindCalendarByName(calendar.Name)
+	if err != nil || found == nil {
+		t.Fatalf("Cannot FindCalendarByName: %v", err)
+	}
+	if !reflect.DeepEqual(found.WeeklyWindows, calendar.WeeklyWindows) || len(found.DateRanges) != 1 ||
+		!found.DateRanges[0].From.Equal(now) || found.Description != calendar.Description || !found.LastUpdate.Equal(now) {
+		t.Fatalf("Unexpected calendar %v", found)
+	}
+
+	calendar.DateRanges = nil
+	calendar.TimeZone = "UTC"
+	err = db.UpdateCalendar(calendar)
+	if err != nil {
+		t.Fatalf("Cannot update calendar: %v", err)
+	}
+	all, err := db.FindAllCalendars()
+	if err != nil || len(all) != 1 || all[0].TimeZone != "UTC" || len(all[0].DateRanges) != 0 {
+		t.Fatalf("Unexpected calendars. Err=%v. Calendars=%v", err, all)
+	}
+
+	err = db.RemoveCalendarByName(calendar.Name)
+	if err != nil {
+		t.Fatalf("Cannot remove calendar: %v", err)
+	}
+	found, err = db.FindCalendarByName(calendar.Name)
+	if err != nil || found != nil {
+		t.Fatalf("Calendar not removed. Err=%v. Calendar=%v", err, found)
+	}
+}
+
+func PauseIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	schedule := makeSchedule(now)
+	err := db.Insert(schedule)
+	if err != nil {
+		t.Fatalf("Cannot insert: %v", err)
+	}
+	defer db.RemoveByName(schedule.Name)
+
+	until := now.Add(time.Hour)
+	err = schedule.Pause("alice", "incident", &until, now)
+	if err != nil {
+		t.Fatalf("Cannot pause: %v", err)
+	}
+	err = db.Update(schedule)
+	if err != nil {
+		t.Fatalf("Cannot update: %v", err)
+	}
+	found, err := db.FindByName(schedule.Name)
+	if err != nil || found == nil {
+		t.Fatalf("Cannot FindByName: %v", err)
+	}
+	assertEquals(t, schedule, *found, "Paused schedule")
+
+	disabled, err := db.FindAllByEnabled(false)
+	if err != nil || len(disabled) != 1 || !disabled[0].PauseExpired(until) {
+		t.Fatalf("Unexpected disabled schedules. Err=%v. Schedules=%v", err, disabled)
+	}
+
+	resumed, err := db.ResumePause(schedule.Name, until.Add(time.Minute), now.Add(time.Minute))
+	if err != nil || resumed {
+		t.Fatalf("Resumed pause until another time. Err=%v", err)
+	}
+	resumed, err = db.ResumePause(schedule.Name, until, now.Add(time.Minute))
+	if err != nil || !resumed {
+		t.Fatalf("Cannot resume pause: %v", err)
+	}
+	schedule.Resume(now.Add(time.Minute))
+	found, err = db.FindByName(schedule.Name)
+	if err != nil || found == nil {
+		t.Fatalf("Cannot FindByName: %v", err)
+	}
+	assertEquals(t, schedule, *found, "Resumed schedule")
+}
+
+func FindSchedulesIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	labels := []map[string]string{
+		{"team": "netops", "region": "eu"},
+		{"team": "netops", "region": "us", "deprecated": "true"},
+		{"team": "inventory", "region": "eu"},
+		nil,
+	}
+	for i, scheduleLabels := range labels {
+		schedule := makeSchedule(now)
+		schedule.Name = fmt.Sprintf("Name%d", i)
+		schedule.Labels = scheduleLabels
+		err := db.Insert(schedule)
+		if err != nil {
+			t.Fatalf("Cannot insert: %v", err)
+		}
+		defer db.RemoveByName(schedule.Name)
+	}
+
+	cases := map[string][]string{
+		"":                              {"Name0", "Name1", "Name2", "Name3"},
+		"team=netops":                   {"Name0", "Name1"},
+		"team=netops,region in (eu,us)": {"Name0", "Name1"},
+		"region notin (us)":             {"Name0", "Name2", "Name3"},
+		"team!=netops":                  {"Name2", "Name3"},
+		"deprecated":                    {"Name1"},
+		"team,!deprecated":              {"Name0", "Name2"},
+	}
+	for selectorString, expected := range cases {
+		selector, err := ifc.ParseLabelSelector(selectorString)
+		if err != nil {
+			t.Fatalf("Cannot parse label selector: %v", err)
+		}
+		schedules, err := db.FindSchedules(ifc.ScheduleFilter{WorkflowName: "WorkflowName", LabelSelector: selector})
+		if err != nil {
+			t.Fatalf("Cannot FindSchedules: %v", err)
+		}
+		names := make([]string, 0)
+		for _, schedule := range schedules {
+			names = append(names, schedule.Name)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Unexpected schedules selected by '%s': %v", selectorString, names)
+		}
+	}
+
+	schedules, err := db.FindSchedules(ifc.ScheduleFilter{WorkflowName: "Other"})
+	if err != nil || len(schedules) != 0 {
+		t.Fatalf("Unexpected schedules of other workflow. Err=%v. Schedules=%v", err, schedules)
+	}
+}
+
+func UpdateNextFireTimeIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	schedule := makeSchedule(now)
+	err := db.Insert(schedule)
+	if err != nil {
+		t.Fatalf("Cannot insert: %v", err)
+	}
+	defer db.RemoveByName(schedule.Name)
+
+	schedule.NextFireTime = &now
+	err = db.UpdateNextFireTime(schedule.Name, &now)
+	if err != nil {
+		t.Fatalf("Cannot update: %v", err)
+	}
+	// selected WorkflowContext is never null
+	schedule.WorkflowContext = make(map[string]interface{})
+	actual := ExpectTableSize(db, 1, "after update", t)[0]
+	assertEquals(t, schedule, actual, "Updated != selected")
+
+	schedule.NextFireTime = nil
+	err = db.UpdateNextFireTime(schedule.Name, nil)
+	if err != nil {
+		t.Fatalf("Cannot clear: %v", err)
+	}
+	actual = ExpectTableSize(db, 1, "after clear", t)[0]
+	assertEquals(t, schedule, actual, "Cleared != selected")
+}
+
+func FilterSchedulesIntegration(t *testing.T, dbGetter func(*testing.T) ifc.DB) {
+	db := dbGetter(t)
+	now := time.Now().Truncate(time.Millisecond)
+	hour := time.Hour
+	schedules := []struct {
+		name         string
+		enabled      bool
+		status       string
+		cronString   string
+		toDate       *time.Time
+		lastUpdate   time.Time
+		nextFireTime *time.Time
+		lastFireTime *time.Time
+	}{
+		{"a-backup", true, "FAILED", "0 * * * *", nil, now.Add(-3 * hour), timePtr(now.Add(2 * hour)), timePtr(now.Add(-2 * hour))},
+		{"b_backup", true, "COMPLETED", "0 * * * *", nil, now.Add(-2 * hour), timePtr(now.Add(hour)), nil},
+		{"c-inventory-BACKUP", false, "FAILED", "*/5 * * * *", timePtr(now.Add(-hour)), now.Add(-hour), nil, timePtr(now.Add(-hour))},
+		{"d-sync", true, "FAILED", "*/5 * * * *", nil, now, timePtr(now.Add(3 * hour)), timePtr(now.Add(-3 * hour))},
+	}
+	for _, s := range schedules {
+		schedule := makeSchedule(now)
+		schedule.Name = s.name
+		schedule.Enabled = s.enabled
+		schedule.Status = s.status
+		schedule.CronString = s.cronString
+		schedule.ToDate = s.toDate
+		schedule.LastUpdate = s.lastUpdate
+		schedule.NextFireTime = s.nextFireTime
+		schedule.LastFireTime = s.lastFireTime
+		err := db.Insert(schedule)
+		if err != nil {
+			t.Fatalf("Cannot insert: %v", err)
+		}
+		defer db.RemoveByName(schedule.Name)
+	}
+
+	enabled := true
+	lastUpdateAfter := now.Add(-150 * time.Minute)
+	lastUpdateBefore := now.Add(-30 * time.Minute)
+	cases := map[string]struct {
+		filter   ifc.ScheduleFilter
+		expected []string
+	}{
+		"all":             {ifc.ScheduleFilter{}, []string{"a-backup", "b_backup", "c-inventory-BACKUP", "d-sync"}},
+		"name prefix":     {ifc.ScheduleFilter{NamePrefix: "b_"}, []string{"b_backup"}},
+		"escaped prefix":  {ifc.ScheduleFilter{NamePrefix: "a_"}, []string{}},
+		"name contains":   {ifc.ScheduleFilter{NameContains: "BACKUP"}, []string{"a-backup", "b_backup", "c-inventory-BACKUP"}},
+		"enabled":         {ifc.ScheduleFilter{Enabled: &enabled}, []string{"a-backup", "b_backup", "d-sync"}},
+		"status":          {ifc.ScheduleFilter{Status: "FAILED"}, []string{"a-backup", "c-inventory-BACKUP", "d-sync"}},
+		"cron string":     {ifc.ScheduleFilter{CronString: "*/5 * * * *"}, []string{"c-inventory-BACKUP", "d-sync"}},
+		"active at":       {ifc.ScheduleFilter{ActiveAt: &now}, []string{"a-backup", "b_backup", "d-sync"}},
+		"last update":     {ifc.ScheduleFilter{LastUpdateAfter: &lastUpdateAfter, LastUpdateBefore: &lastUpdateBefore}, []string{"b_backup", "c-inventory-BACKUP"}},
+		"name descending": {ifc.ScheduleFilter{SortDescending: true}, []string{"d-sync", "c-inventory-BACKUP", "b_backup", "a-backup"}},
+		"last update ascending": {ifc.ScheduleFilter{SortBy: ifc.ScheduleSortLastUpdate},
+			[]string{"a-backup", "b_backup", "c-inventory-BACKUP", "d-sync"}},
+		"next run ascending": {ifc.ScheduleFilter{SortBy: ifc.ScheduleSortNextRun},
+			[]string{"b_backup", "a-backup", "d-sync", "c-inventory-BACKUP"}},
+		"next run descending": {ifc.ScheduleFilter{SortBy: ifc.ScheduleSortNextRun, SortDescending: true},
+			[]string{"d-sync", "a-backup", "b_backup", "c-inventory-BACKUP"}},
+		"status descending": {ifc.ScheduleFilter{SortBy: ifc.ScheduleSortStatus, SortDescending: true},
+			[]string{"a-backup", "c-inventory-BACKUP", "d-sync", "b_backup"}},
+		"failed enabled by last run": {ifc.ScheduleFilter{Status: "FAILED", Enabled: &enabled, SortBy: ifc.ScheduleSortLastRun, SortDescending: true},
+			[]string{"a-backup", "d-sync"}},
+	}
+	for name, c := range cases {
+		found, err := db.FindSchedules(c.filter)
+		if err != nil {
+			t.Fatalf("Cannot FindSchedules %s: %v", name, err)
+		}
+		names := make([]string, 0)
+		for _, schedule := range found {
+			names = append(names, schedule.Name)
+		}
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("Unexpected schedules found by %s: %v", name, names)
+		}
+	}
+
+	_, err := db.FindSchedules(ifc.ScheduleFilter{SortBy: "UNKNOWN"})
+	if err == nil {
+		t.Fatalf("Expected error of unknown sort order")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
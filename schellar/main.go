@@ -5,8 +5,12 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
+	// time zones of schedules must be available also in images without tzdata
+	_ "time/tzdata"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -23,16 +27,23 @@ import (
 const defaultPort = "3000"
 const defaultPlaygoundQueryEndpoint = "/query"
 
-var config = scheduler.Configuration
-
 func main() {
 
 	setupLogging()
+	scheduler.InitConfiguration()
+
+	if scheduler.Configuration.EventsEnabled {
+		webhook := scheduler.NewWebhookEventSource()
+		scheduler.RegisterWorkflowEventSource(webhook)
+		http.Handle("/events/workflow", webhook)
+	}
 
 	if err := scheduler.StartScheduler(); err != nil {
 		logrus.Fatalf("Error during scheduler startup: %v", err)
 	}
 
+	go stopOnSignal()
+
 	port := getPort()
 	startApi()
 
@@ -57,6 +68,15 @@ func setupLogging() {
 	}
 }
 
+func stopOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	sig := <-signals
+	logrus.Infof("Received signal %s, stopping scheduler", sig)
+	scheduler.StopScheduler()
+	os.Exit(0)
+}
+
 func getPort() string {
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -108,6 +128,7 @@ func startApi() {
 	http.Handle("/query", srv)
 	http.Handle("/metrics", promhttp.Handler())
 	http.HandleFunc("/liveness", getLiveness)
+	http.HandleFunc("/readiness", getReadiness)
 }
 
 func getLiveness(w http.ResponseWriter, r *http.Request) {
@@ -118,3 +139,17 @@ func getLiveness(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Write([]byte("OK"))
 }
+
+// getReadiness reports whether this replica is the leader running schedule timers
+func getReadiness(w http.ResponseWriter, r *http.Request) {
+	logrus.Debugf("getReadiness r r=%v", r)
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if scheduler.IsLeader() {
+		w.Write([]byte("LEADER"))
+	} else {
+		w.Write([]byte("STANDBY"))
+	}
+}
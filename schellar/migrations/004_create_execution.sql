@@ -0,0 +1,17 @@
+create table execution(
+  execution_id varchar(36) primary key,
+  schedule_name varchar(100) not null references schedule(schedule_name) on delete cascade,
+  fire_time timestamptz not null,
+  workflow_id varchar(100) not null,
+  execution_status varchar(20) not null,
+  start_time timestamptz,
+  end_time timestamptz,
+  error_message text not null
+);
+
+create index execution_schedule_name_fire_time on execution(schedule_name, fire_time desc);
+create index execution_workflow_id on execution(workflow_id);
+
+---- create above / drop below ----
+
+--drop table execution;
@@ -0,0 +1 @@
+ALTER TABLE execution ADD COLUMN trigger_source varchar(20) not null default 'TIMER';
@@ -0,0 +1,9 @@
+create table lease(
+  lease_name varchar(100) primary key,
+  holder varchar(100) not null,
+  expires_at timestamptz not null
+);
+
+---- create above / drop below ----
+
+--drop table lease;
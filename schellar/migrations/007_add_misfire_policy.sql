@@ -0,0 +1,3 @@
+ALTER TABLE schedule ADD COLUMN misfire_policy varchar(20) not null default 'SKIP';
+ALTER TABLE schedule ADD COLUMN misfire_max_count int not null default 0;
+ALTER TABLE schedule ADD COLUMN last_fire_time timestamptz;
@@ -0,0 +1 @@
+ALTER TABLE schedule ADD COLUMN time_zone varchar(64) not null default '';
@@ -0,0 +1,2 @@
+ALTER TABLE schedule ALTER COLUMN cron_string TYPE varchar(100);
+ALTER TABLE schedule ADD COLUMN cron_format varchar(20) not null default 'STANDARD';
@@ -0,0 +1,2 @@
+ALTER TABLE schedule ADD COLUMN schedule_condition varchar(20) not null default 'OK';
+ALTER TABLE schedule ADD COLUMN condition_message text not null default '';
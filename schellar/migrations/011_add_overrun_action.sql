@@ -0,0 +1,2 @@
+ALTER TABLE schedule ADD COLUMN max_run_duration int not null default 0;
+ALTER TABLE schedule ADD COLUMN on_overrun varchar(30) not null default 'NONE';
@@ -0,0 +1,2 @@
+ALTER TABLE schedule ADD COLUMN retry_policy jsonb not null default '{}';
+ALTER TABLE execution ADD COLUMN attempts int not null default 1;
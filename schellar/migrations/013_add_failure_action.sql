@@ -0,0 +1,3 @@
+ALTER TABLE schedule ADD COLUMN on_failure varchar(20) not null default 'NONE';
+ALTER TABLE schedule ADD COLUMN on_failure_max_count int not null default 0;
+ALTER TABLE execution ADD COLUMN recoveries int not null default 0;
@@ -0,0 +1,3 @@
+ALTER TABLE schedule ADD COLUMN concurrency_policy varchar(20) not null default 'FORBID';
+ALTER TABLE schedule ADD COLUMN max_concurrent_runs int not null default 0;
+UPDATE schedule SET concurrency_policy='ALLOW' WHERE parallel_runs;
@@ -0,0 +1,2 @@
+ALTER TABLE schedule ADD COLUMN trigger_mode varchar(20) not null default 'CRON';
+ALTER TABLE schedule ADD COLUMN depends_on jsonb;
@@ -0,0 +1,17 @@
+ALTER TABLE schedule ADD COLUMN output_mapping jsonb;
+ALTER TABLE schedule ADD COLUMN update_context_on_failure boolean not null default false;
+ALTER TABLE schedule ADD COLUMN max_context_bytes int not null default 0;
+
+create table context_version(
+  schedule_name varchar(100) not null references schedule(schedule_name) on delete cascade,
+  version int not null,
+  workflow_context jsonb not null,
+  source varchar(20) not null,
+  workflow_id varchar(100) not null,
+  created timestamptz not null,
+  primary key (schedule_name, version)
+);
+
+---- create above / drop below ----
+
+--drop table context_version;
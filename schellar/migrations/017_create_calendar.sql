@@ -0,0 +1,15 @@
+create table calendar(
+  calendar_name varchar(100) primary key,
+  description text not null,
+  time_zone varchar(64) not null,
+  date_ranges jsonb,
+  weekly_windows jsonb,
+  last_update timestamptz not null
+);
+
+ALTER TABLE schedule ADD COLUMN include_calendars jsonb;
+ALTER TABLE schedule ADD COLUMN exclude_calendars jsonb;
+
+---- create above / drop below ----
+
+--drop table calendar;
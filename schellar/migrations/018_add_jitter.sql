@@ -0,0 +1,2 @@
+ALTER TABLE schedule ADD COLUMN jitter_seconds int not null default 0;
+ALTER TABLE schedule ADD COLUMN jitter_mode varchar(20) not null default 'RANDOM';
@@ -0,0 +1,3 @@
+ALTER TABLE execution ADD COLUMN workflow_name varchar(100) not null default '';
+
+create index execution_workflow_name_status on execution(workflow_name, execution_status);
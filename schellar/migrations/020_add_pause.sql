@@ -0,0 +1,4 @@
+ALTER TABLE schedule ADD COLUMN paused_by varchar(100) not null default '';
+ALTER TABLE schedule ADD COLUMN pause_reason text not null default '';
+ALTER TABLE schedule ADD COLUMN paused_at timestamptz;
+ALTER TABLE schedule ADD COLUMN paused_until timestamptz;
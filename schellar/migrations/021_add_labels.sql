@@ -0,0 +1,3 @@
+ALTER TABLE schedule ADD COLUMN labels jsonb not null default '{}';
+
+create index schedule_labels on schedule using gin (labels);
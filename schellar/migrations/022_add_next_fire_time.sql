@@ -0,0 +1,4 @@
+ALTER TABLE schedule ADD COLUMN next_fire_time timestamptz;
+
+create index schedule_next_fire_time on schedule(next_fire_time);
+create index schedule_last_update on schedule(last_update);
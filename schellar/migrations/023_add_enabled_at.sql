@@ -0,0 +1 @@
+ALTER TABLE schedule ADD COLUMN enabled_at timestamptz;
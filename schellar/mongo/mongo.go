@@ -3,6 +3,7 @@ package mongo
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 )
 
 type MongoDB struct {
@@ -46,9 +48,147 @@ func InitDB() ifc.DB {
 	}
 	mongoSession = ms
 	logrus.Infof("Connected to MongoDB successfully")
+	err = mongoSession.DB(dbName).C("schedules").EnsureIndexKey("labelPairs")
+	if err != nil {
+		logrus.Errorf("Couldn't create index of schedule labels. err=%s", err)
+	}
+	for _, key := range []string{"nextFireTime", "lastUpdate"} {
+		err = mongoSession.DB(dbName).C("schedules").EnsureIndexKey(key)
+		if err != nil {
+			logrus.Errorf("Couldn't create index of schedule %s. err=%s", key, err)
+		}
+	}
+	for _, keys := range [][]string{{"workflowId"}, {"scheduleName", "-fireTime"}, {"workflowName", "status"}} {
+		err = mongoSession.DB(dbName).C("executions").EnsureIndexKey(keys...)
+		if err != nil {
+			logrus.Errorf("Couldn't create index of execution %s. err=%s", strings.Join(keys, ","), err)
+		}
+	}
 	return MongoDB{mongoSession, dbName}
 }
 
+// scheduleDocument stores labels of the schedule also as "key=value" pairs, so that label selectors
+// use an index of a single array field instead of arbitrary label keys
+type scheduleDocument struct {
+	ifc.Schedule `bson:",inline"`
+	LabelPairs   []string `bson:"labelPairs"`
+}
+
+func newScheduleDocument(schedule ifc.Schedule) scheduleDocument {
+	return scheduleDocument{Schedule: schedule, LabelPairs: ifc.LabelPairs(schedule.Labels)}
+}
+
+// scheduleQuery returns query of schedules matching the filter
+func scheduleQuery(filter ifc.ScheduleFilter) bson.M {
+	query := bson.M{}
+	if filter.WorkflowName != "" {
+		query["workflowName"] = filter.WorkflowName
+	}
+	if filter.WorkflowVersion != "" {
+		query["workflowVersion"] = filter.WorkflowVersion
+	}
+	if filter.Enabled != nil {
+		query["enabled"] = *filter.Enabled
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.CronString != "" {
+		query["cronString"] = filter.CronString
+	}
+	lastUpdate := bson.M{}
+	if filter.LastUpdateAfter != nil {
+		lastUpdate["$gt"] = *filter.LastUpdateAfter
+	}
+	if filter.LastUpdateBefore != nil {
+		lastUpdate["$lt"] = *filter.LastUpdateBefore
+	}
+	if len(lastUpdate) > 0 {
+		query["lastUpdate"] = lastUpdate
+	}
+	conditions := make([]bson.M, 0)
+	if filter.NamePrefix != "" {
+		conditions = append(conditions, bson.M{"name": bson.M{"$regex": "^" + regexp.QuoteMeta(filter.NamePrefix)}})
+	}
+	if filter.NameContains != "" {
+		conditions = append(conditions, bson.M{"name": bson.M{"$regex": regexp.QuoteMeta(filter.NameContains), "$options": "i"}})
+	}
+	if filter.ActiveAt != nil {
+		conditions = append(conditions,
+			bson.M{"$or": []bson.M{{"fromDate": nil}, {"fromDate": bson.M{"$lt": *filter.ActiveAt}}}},
+			bson.M{"$or": []bson.M{{"toDate": nil}, {"toDate": bson.M{"$gt": *filter.ActiveAt}}}})
+	}
+	for _, requirement := range filter.LabelSelector {
+		switch requirement.Operator {
+		case ifc.LabelExists:
+			conditions = append(conditions, bson.M{"labels." + requirement.Key: bson.M{"$exists": true}})
+		case ifc.LabelNotExists:
+			conditions = append(conditions, bson.M{"labels." + requirement.Key: bson.M{"$exists": false}})
+		case ifc.LabelEquals, ifc.LabelIn:
+			conditions = append(conditions, bson.M{"labelPairs": bson.M{"$in": requirement.Pairs()}})
+		case ifc.LabelNotEquals, ifc.LabelNotIn:
+			conditions = append(conditions, bson.M{"labelPairs": bson.M{"$nin": requirement.Pairs()}})
+		}
+	}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+	return query
+}
+
+func (db MongoDB) FindSchedules(filter ifc.ScheduleFilter) ([]ifc.Schedule, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = ifc.ScheduleSortName
+	}
+	field, exists := scheduleSortFields[sortBy]
+	if !exists {
+		return nil, fmt.Errorf("unknown sort order %s", sortBy)
+	}
+	direction := 1
+	if filter.SortDescending {
+		direction = -1
+	}
+
+	st := sc.DB(db.dbName).C("schedules")
+	schedules := make([]ifc.Schedule, 0)
+	if sortBy == ifc.ScheduleSortName {
+		err := st.Find(scheduleQuery(filter)).Sort(sortKey(field, direction)).All(&schedules)
+		return schedules, err
+	}
+	if sortBy != ifc.ScheduleSortNextRun && sortBy != ifc.ScheduleSortLastRun {
+		err := st.Find(scheduleQuery(filter)).Sort(sortKey(field, direction), "name").All(&schedules)
+		return schedules, err
+	}
+	// nulls are sorted first in ascending order, so schedules without the time are moved last explicitly
+	pipeline := []bson.M{
+		{"$match": scheduleQuery(filter)},
+		{"$addFields": bson.M{"sortMissing": bson.M{"$cond": []interface{}{
+			bson.M{"$eq": []interface{}{bson.M{"$ifNull": []interface{}{"$" + field, nil}}, nil}}, 1, 0}}}},
+		{"$sort": bson.D{{Name: "sortMissing", Value: 1}, {Name: field, Value: direction}, {Name: "name", Value: 1}}},
+	}
+	err := st.Pipe(pipeline).All(&schedules)
+	return schedules, err
+}
+
+var scheduleSortFields = map[string]string{
+	ifc.ScheduleSortName:       "name",
+	ifc.ScheduleSortLastUpdate: "lastUpdate",
+	ifc.ScheduleSortNextRun:    "nextFireTime",
+	ifc.ScheduleSortLastRun:    "lastFireTime",
+	ifc.ScheduleSortStatus:     "status",
+}
+
+func sortKey(field string, direction int) string {
+	if direction < 0 {
+		return "-" + field
+	}
+	return field
+}
+
 func (db MongoDB) FindAll() ([]ifc.Schedule, error) {
 	sc := db.mongoSession.Copy()
 	defer sc.Close()
@@ -136,11 +276,49 @@ func (db MongoDB) UpdateStatusAndWorkflowContext(schedule ifc.Schedule) error {
 	return sch.Update(map[string]interface{}{"name": schedule.Name}, map[string]interface{}{"$set": scheduleMap})
 }
 
+func (db MongoDB) UpdateLastFireTime(scheduleName string, lastFireTime time.Time) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sch := sc.DB(db.dbName).C("schedules")
+	return sch.Update(map[string]interface{}{"name": scheduleName}, map[string]interface{}{"$set": map[string]interface{}{"lastFireTime": lastFireTime}})
+}
+
+func (db MongoDB) UpdateNextFireTime(scheduleName string, nextFireTime *time.Time) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sch := sc.DB(db.dbName).C("schedules")
+	return sch.Update(map[string]interface{}{"name": scheduleName}, map[string]interface{}{"$set": map[string]interface{}{"nextFireTime": nextFireTime}})
+}
+
+func (db MongoDB) UpdateCondition(scheduleName string, condition string, conditionMessage string) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sch := sc.DB(db.dbName).C("schedules")
+	return sch.Update(map[string]interface{}{"name": scheduleName}, map[string]interface{}{"$set": map[string]interface{}{"condition": condition, "conditionMessage": conditionMessage}})
+}
+
+func (db MongoDB) ResumePause(scheduleName string, pausedUntil time.Time, enabledAt time.Time) (bool, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sch := sc.DB(db.dbName).C("schedules")
+	err := sch.Update(bson.M{"name": scheduleName, "enabled": false, "pausedUntil": pausedUntil},
+		bson.M{"$set": bson.M{"enabled": true, "enabledAt": enabledAt, "pausedBy": "", "pauseReason": "",
+			"pausedAt": nil, "pausedUntil": nil}})
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (db MongoDB) Insert(schedule ifc.Schedule) error {
 	sc := db.mongoSession.Copy()
 	defer sc.Close()
 	st := sc.DB(db.dbName).C("schedules")
-	return st.Insert(schedule)
+	return st.Insert(newScheduleDocument(schedule))
 }
 
 func (db MongoDB) Update(schedule ifc.Schedule) error {
@@ -148,7 +326,7 @@ func (db MongoDB) Update(schedule ifc.Schedule) error {
 	defer sc.Close()
 
 	st := sc.DB(db.dbName).C("schedules")
-	return st.Update(map[string]interface{}{"name": schedule.Name}, map[string]interface{}{"$set": schedule})
+	return st.Update(map[string]interface{}{"name": schedule.Name}, map[string]interface{}{"$set": newScheduleDocument(schedule)})
 }
 
 func (db MongoDB) RemoveByName(scheduleName string) error {
@@ -156,5 +334,234 @@ func (db MongoDB) RemoveByName(scheduleName string) error {
 	defer sc.Close()
 
 	st := sc.DB(db.dbName).C("schedules")
-	return st.Remove(map[string]interface{}{"name": scheduleName})
+	err := st.Remove(map[string]interface{}{"name": scheduleName})
+	if err != nil {
+		return err
+	}
+	se := sc.DB(db.dbName).C("executions")
+	_, err = se.RemoveAll(map[string]interface{}{"scheduleName": scheduleName})
+	if err != nil {
+		return err
+	}
+	sv := sc.DB(db.dbName).C("contextVersions")
+	_, err = sv.RemoveAll(map[string]interface{}{"scheduleName": scheduleName})
+	return err
+}
+
+func (db MongoDB) InsertExecution(execution ifc.Execution) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	se := sc.DB(db.dbName).C("executions")
+	return se.Insert(execution)
+}
+
+func (db MongoDB) UpdateExecution(execution ifc.Execution) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	se := sc.DB(db.dbName).C("executions")
+	return se.Update(map[string]interface{}{"id": execution.ID}, map[string]interface{}{"$set": execution})
+}
+
+func (db MongoDB) FindExecutionByWorkflowID(workflowID string) (*ifc.Execution, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	se := sc.DB(db.dbName).C("executions")
+	executions := make([]ifc.Execution, 0)
+	err := se.Find(map[string]interface{}{"workflowId": workflowID}).All(&executions)
+	if err != nil {
+		return nil, err
+	}
+	if len(executions) == 1 {
+		return &executions[0], nil
+	} else if len(executions) == 0 {
+		return nil, nil
+	}
+	return nil, errors.New(
+		fmt.Sprintf(
+			"Unexpected result for FindExecutionByWorkflowID('%s'): Found %d items",
+			workflowID, len(executions),
+		))
+}
+
+func executionQuery(filter ifc.ExecutionFilter) bson.M {
+	query := bson.M{}
+	if filter.ScheduleName != "" {
+		query["scheduleName"] = filter.ScheduleName
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.FiredBefore != nil {
+		query["fireTime"] = bson.M{"$lt": *filter.FiredBefore}
+	}
+	if filter.ExcludeStatus != "" {
+		if filter.Status != "" {
+			query["status"] = bson.M{"$eq": filter.Status, "$ne": filter.ExcludeStatus}
+		} else {
+			query["status"] = bson.M{"$ne": filter.ExcludeStatus}
+		}
+	}
+	if filter.WorkflowName != "" {
+		query["workflowName"] = filter.WorkflowName
+	}
+	return query
+}
+
+func (db MongoDB) FindExecutions(filter ifc.ExecutionFilter, after string, limit int) ([]ifc.Execution, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	se := sc.DB(db.dbName).C("executions")
+	query := executionQuery(filter)
+	if after != "" {
+		var cursor ifc.Execution
+		err := se.Find(map[string]interface{}{"id": after}).One(&cursor)
+		if err == mgo.ErrNotFound {
+			return make([]ifc.Execution, 0), nil
+		} else if err != nil {
+			return nil, err
+		}
+		query["$or"] = []bson.M{
+			{"fireTime": bson.M{"$lt": cursor.FireTime}},
+			{"fireTime": cursor.FireTime, "id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+	executions := make([]ifc.Execution, 0)
+	err := se.Find(query).Sort("-fireTime", "-id").Limit(limit).All(&executions)
+	return executions, err
+}
+
+func (db MongoDB) CountExecutions(filter ifc.ExecutionFilter) (int, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	se := sc.DB(db.dbName).C("executions")
+	return se.Find(executionQuery(filter)).Count()
+}
+
+func (db MongoDB) AcquireLease(leaseName string, holder string, ttl time.Duration) (bool, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sl := sc.DB(db.dbName).C("leases")
+	now := time.Now()
+	// when the lease is held by somebody else the selector does not match
+	// and upsert fails on duplicate _id
+	_, err := sl.Upsert(
+		bson.M{"_id": leaseName, "$or": []bson.M{{"holder": holder}, {"expiresAt": bson.M{"$lt": now}}}},
+		bson.M{"$set": bson.M{"holder": holder, "expiresAt": now.Add(ttl)}})
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (db MongoDB) ReleaseLease(leaseName string, holder string) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sl := sc.DB(db.dbName).C("leases")
+	err := sl.Remove(bson.M{"_id": leaseName, "holder": holder})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (db MongoDB) InsertContextVersion(version ifc.ContextVersion, keep int) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sv := sc.DB(db.dbName).C("contextVersions")
+	var latest ifc.ContextVersion
+	err := sv.Find(bson.M{"scheduleName": version.ScheduleName}).Sort("-version").One(&latest)
+	if err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	version.Version = latest.Version + 1
+	err = sv.Insert(version)
+	if err != nil {
+		return err
+	}
+	if keep > 0 {
+		_, err = sv.RemoveAll(bson.M{"scheduleName": version.ScheduleName, "version": bson.M{"$lte": version.Version - keep}})
+	}
+	return err
+}
+
+func (db MongoDB) FindContextVersions(scheduleName string, limit int) ([]ifc.ContextVersion, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sv := sc.DB(db.dbName).C("contextVersions")
+	versions := make([]ifc.ContextVersion, 0)
+	err := sv.Find(bson.M{"scheduleName": scheduleName}).Sort("-version").Limit(limit).All(&versions)
+	return versions, err
+}
+
+func (db MongoDB) FindContextVersion(scheduleName string, version int) (*ifc.ContextVersion, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	sv := sc.DB(db.dbName).C("contextVersions")
+	var result ifc.ContextVersion
+	err := sv.Find(bson.M{"scheduleName": scheduleName, "version": version}).One(&result)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (db MongoDB) FindAllCalendars() ([]ifc.Calendar, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	scal := sc.DB(db.dbName).C("calendars")
+	calendars := make([]ifc.Calendar, 0)
+	err := scal.Find(nil).Sort("name").All(&calendars)
+	return calendars, err
+}
+
+func (db MongoDB) FindCalendarByName(calendarName string) (*ifc.Calendar, error) {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	scal := sc.DB(db.dbName).C("calendars")
+	var calendar ifc.Calendar
+	err := scal.Find(bson.M{"name": calendarName}).One(&calendar)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return &calendar, err
+}
+
+func (db MongoDB) InsertCalendar(calendar ifc.Calendar) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	scal := sc.DB(db.dbName).C("calendars")
+	return scal.Insert(calendar)
+}
+
+func (db MongoDB) UpdateCalendar(calendar ifc.Calendar) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	scal := sc.DB(db.dbName).C("calendars")
+	return scal.Update(bson.M{"name": calendar.Name}, bson.M{"$set": calendar})
+}
+
+func (db MongoDB) RemoveCalendarByName(calendarName string) error {
+	sc := db.mongoSession.Copy()
+	defer sc.Close()
+
+	scal := sc.DB(db.dbName).C("calendars")
+	err := scal.Remove(bson.M{"name": calendarName})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
 }
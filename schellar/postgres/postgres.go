@@ -16,10 +16,10 @@ import (
 )
 
 type PostgresDB struct {
-	connectionPool pgxpool.Pool
+	connectionPool *pgxpool.Pool
 }
 
-func runMigrations(connectionPool pgxpool.Pool) {
+func runMigrations(connectionPool *pgxpool.Pool) {
 	conn, err := connectionPool.Acquire(context.Background())
 	if err != nil {
 		logrus.Fatalf("Unable to acquire connection to database: %v", err)
@@ -72,8 +72,8 @@ func InitDB() ifc.DB {
 	if err != nil {
 		logrus.Fatalf("Unable to connection to database: %v", err)
 	}
-	runMigrations(*connectionPool)
-	return PostgresDB{*connectionPool}
+	runMigrations(connectionPool)
+	return PostgresDB{connectionPool}
 }
 
 func (db PostgresDB) queryAll(sql string, args ...interface{}) ([]ifc.Schedule, error) {
@@ -100,11 +100,51 @@ func (db PostgresDB) queryAll(sql string, args ...interface{}) ([]ifc.Schedule,
 			CorrelationID       string
 			TaskToDomain        map[string]string
 			LastUpdate          time.Time
+			MisfirePolicy       string
+			MisfireMaxCount     int
+			LastFireTime        *time.Time
+			TimeZone            string
+			CronFormat          string
+			Condition           string
+			ConditionMessage    string
+			MaxRunDuration      int
+			OnOverrun           string
+			RetryPolicy         ifc.RetryPolicy
+			OnFailure           string
+			OnFailureMaxCount   int
+			ConcurrencyPolicy   string
+			MaxConcurrentRuns   int
+			TriggerMode         string
+			DependsOn           []ifc.Dependency
+			OutputMapping       map[string]string
+			UpdateOnFailure     bool
+			MaxContextBytes     int
+			IncludeCalendars    []string
+			ExcludeCalendars    []string
+			JitterSeconds       int
+			JitterMode          string
+			PausedBy            string
+			PauseReason         string
+			PausedAt            *time.Time
+			PausedUntil         *time.Time
+			Labels              map[string]string
+			NextFireTime        *time.Time
+			EnabledAt           *time.Time
 		)
 
 		err = rows.Scan(&ScheduleName, &Enabled, &Status, &WorkflowName, &WorkflowVersion,
 			&WorkflowContext, &CronString, &ParallelRuns, &CheckWarningSeconds,
 			&FromDate, &ToDate, &CorrelationID, &TaskToDomain, &LastUpdate,
+			&MisfirePolicy, &MisfireMaxCount, &LastFireTime, &TimeZone,
+			&CronFormat, &Condition, &ConditionMessage,
+			&MaxRunDuration, &OnOverrun, &RetryPolicy,
+			&OnFailure, &OnFailureMaxCount, &ConcurrencyPolicy, &MaxConcurrentRuns,
+			&TriggerMode, &DependsOn,
+			&OutputMapping, &UpdateOnFailure, &MaxContextBytes,
+			&IncludeCalendars, &ExcludeCalendars,
+			&JitterSeconds, &JitterMode,
+			&PausedBy, &PauseReason, &PausedAt, &PausedUntil,
+			&Labels, &NextFireTime, &EnabledAt,
 		)
 		if err != nil {
 			return nil, err
@@ -112,9 +152,57 @@ func (db PostgresDB) queryAll(sql string, args ...interface{}) ([]ifc.Schedule,
 		if WorkflowContext == nil {
 			WorkflowContext = make(map[string]interface{})
 		}
-		schedule := ifc.Schedule{ScheduleName, Enabled, Status, WorkflowName, WorkflowVersion,
-			WorkflowContext, CronString, ParallelRuns, CheckWarningSeconds,
-			FromDate, ToDate, LastUpdate, CorrelationID, TaskToDomain}
+		if len(Labels) == 0 {
+			// stored as empty object
+			Labels = nil
+		}
+		schedule := ifc.Schedule{
+			Name:                ScheduleName,
+			Enabled:             Enabled,
+			Status:              Status,
+			WorkflowName:        WorkflowName,
+			WorkflowVersion:     WorkflowVersion,
+			WorkflowContext:     WorkflowContext,
+			CronString:          CronString,
+			ParallelRuns:        ParallelRuns,
+			CheckWarningSeconds: CheckWarningSeconds,
+			FromDate:            FromDate,
+			ToDate:              ToDate,
+			LastUpdate:          LastUpdate,
+			CorrelationID:       CorrelationID,
+			TaskToDomain:        TaskToDomain,
+			MisfirePolicy:       MisfirePolicy,
+			MisfireMaxCount:     MisfireMaxCount,
+			LastFireTime:        LastFireTime,
+			TimeZone:            TimeZone,
+			CronFormat:          CronFormat,
+			Condition:           Condition,
+			ConditionMessage:    ConditionMessage,
+			MaxRunDuration:      MaxRunDuration,
+			OnOverrun:           OnOverrun,
+			RetryPolicy:         RetryPolicy,
+			OnFailure:           OnFailure,
+			OnFailureMaxCount:   OnFailureMaxCount,
+			ConcurrencyPolicy:   ConcurrencyPolicy,
+			MaxConcurrentRuns:   MaxConcurrentRuns,
+			TriggerMode:         TriggerMode,
+			DependsOn:           DependsOn,
+
+			OutputMapping:          OutputMapping,
+			UpdateContextOnFailure: UpdateOnFailure,
+			MaxContextBytes:        MaxContextBytes,
+			IncludeCalendars:       IncludeCalendars,
+			ExcludeCalendars:       ExcludeCalendars,
+			JitterSeconds:          JitterSeconds,
+			JitterMode:             JitterMode,
+			PausedBy:               PausedBy,
+			PauseReason:            PauseReason,
+			PausedAt:               PausedAt,
+			PausedUntil:            PausedUntil,
+			Labels:                 Labels,
+			NextFireTime:           NextFireTime,
+			EnabledAt:              EnabledAt,
+		}
 
 		schedules = append(schedules, schedule)
 	}
@@ -136,7 +224,37 @@ from_date,
 to_date,
 correlation_id,
 task_to_domain,
-last_update`
+last_update,
+misfire_policy,
+misfire_max_count,
+last_fire_time,
+time_zone,
+cron_format,
+schedule_condition,
+condition_message,
+max_run_duration,
+on_overrun,
+retry_policy,
+on_failure,
+on_failure_max_count,
+concurrency_policy,
+max_concurrent_runs,
+trigger_mode,
+depends_on,
+output_mapping,
+update_context_on_failure,
+max_context_bytes,
+include_calendars,
+exclude_calendars,
+jitter_seconds,
+jitter_mode,
+paused_by,
+pause_reason,
+paused_at,
+paused_until,
+labels,
+next_fire_time,
+enabled_at`
 
 func (db PostgresDB) FindAll() ([]ifc.Schedule, error) {
 	return db.queryAll("SELECT " + rowNames + " FROM schedule ORDER BY schedule_name ASC")
@@ -146,6 +264,130 @@ func (db PostgresDB) FindAllByWorkflowType(workflowName string, workflowId strin
 	return db.queryAll("SELECT "+rowNames+" FROM schedule WHERE workflow_name=$1 and workflow_version=$2 ORDER BY schedule_name ASC", workflowName, workflowId)
 }
 
+func (db PostgresDB) FindSchedules(filter ifc.ScheduleFilter) ([]ifc.Schedule, error) {
+	where, args := scheduleFilterClause(filter)
+	orderBy, err := scheduleOrderClause(filter)
+	if err != nil {
+		return nil, err
+	}
+	return db.queryAll("SELECT "+rowNames+" FROM schedule"+where+orderBy, args...)
+}
+
+var scheduleSortColumns = map[string]string{
+	ifc.ScheduleSortName:       "schedule_name",
+	ifc.ScheduleSortLastUpdate: "last_update",
+	ifc.ScheduleSortNextRun:    "next_fire_time",
+	ifc.ScheduleSortLastRun:    "last_fire_time",
+	ifc.ScheduleSortStatus:     "workflow_status",
+}
+
+// scheduleOrderClause returns ORDER BY clause of the filter, schedules without the sorted value are last
+func scheduleOrderClause(filter ifc.ScheduleFilter) (string, error) {
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = ifc.ScheduleSortName
+	}
+	column, exists := scheduleSortColumns[sortBy]
+	if !exists {
+		return "", fmt.Errorf("unknown sort order %s", sortBy)
+	}
+	direction := "ASC"
+	if filter.SortDescending {
+		direction = "DESC"
+	}
+	if sortBy == ifc.ScheduleSortName {
+		return " ORDER BY schedule_name " + direction, nil
+	}
+	return fmt.Sprintf(" ORDER BY %s %s NULLS LAST, schedule_name ASC", column, direction), nil
+}
+
+// escapeLike escapes wildcards of LIKE patterns
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// scheduleFilterClause returns WHERE clause of the filter. Label requirements use containment (@>)
+// and existence (?) operators supported by the GIN index of labels.
+func scheduleFilterClause(filter ifc.ScheduleFilter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	if filter.WorkflowName != "" {
+		args = append(args, filter.WorkflowName)
+		conditions = append(conditions, fmt.Sprintf("workflow_name=$%d", len(args)))
+	}
+	if filter.WorkflowVersion != "" {
+		args = append(args, filter.WorkflowVersion)
+		conditions = append(conditions, fmt.Sprintf("workflow_version=$%d", len(args)))
+	}
+	if filter.NamePrefix != "" {
+		args = append(args, escapeLike(filter.NamePrefix)+"%")
+		conditions = append(conditions, fmt.Sprintf("schedule_name LIKE $%d", len(args)))
+	}
+	if filter.NameContains != "" {
+		args = append(args, "%"+escapeLike(filter.NameContains)+"%")
+		conditions = append(conditions, fmt.Sprintf("schedule_name ILIKE $%d", len(args)))
+	}
+	if filter.Enabled != nil {
+		args = append(args, *filter.Enabled)
+		conditions = append(conditions, fmt.Sprintf("is_enabled=$%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("workflow_status=$%d", len(args)))
+	}
+	if filter.CronString != "" {
+		args = append(args, filter.CronString)
+		conditions = append(conditions, fmt.Sprintf("cron_string=$%d", len(args)))
+	}
+	if filter.ActiveAt != nil {
+		args = append(args, *filter.ActiveAt)
+		conditions = append(conditions, fmt.Sprintf("(from_date IS NULL OR from_date<$%d) AND (to_date IS NULL OR to_date>$%d)",
+			len(args), len(args)))
+	}
+	if filter.LastUpdateAfter != nil {
+		args = append(args, *filter.LastUpdateAfter)
+		conditions = append(conditions, fmt.Sprintf("last_update>$%d", len(args)))
+	}
+	if filter.LastUpdateBefore != nil {
+		args = append(args, *filter.LastUpdateBefore)
+		conditions = append(conditions, fmt.Sprintf("last_update<$%d", len(args)))
+	}
+	for _, requirement := range filter.LabelSelector {
+		switch requirement.Operator {
+		case ifc.LabelExists, ifc.LabelNotExists:
+			args = append(args, requirement.Key)
+			condition := fmt.Sprintf("labels ? $%d", len(args))
+			if requirement.Operator == ifc.LabelNotExists {
+				condition = "NOT " + condition
+			}
+			conditions = append(conditions, condition)
+		default:
+			contained := make([]string, 0, len(requirement.Values))
+			for _, value := range requirement.Values {
+				args = append(args, map[string]string{requirement.Key: value})
+				contained = append(contained, fmt.Sprintf("labels @> $%d", len(args)))
+			}
+			condition := "(" + strings.Join(contained, " OR ") + ")"
+			if requirement.Operator == ifc.LabelNotEquals || requirement.Operator == ifc.LabelNotIn {
+				condition = "NOT " + condition
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// labelsOrEmpty stores missing labels as empty object, so that NOT conditions match schedules without labels
+func labelsOrEmpty(labels map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+	return labels
+}
+
 func (db PostgresDB) FindAllByEnabled(enabled bool) ([]ifc.Schedule, error) {
 	return db.queryAll("SELECT "+rowNames+" FROM schedule WHERE is_enabled=$1 ORDER BY schedule_name ASC", enabled)
 }
@@ -174,7 +416,7 @@ func (db PostgresDB) FindByStatus(status string) ([]ifc.Schedule, error) {
 
 func (db PostgresDB) Insert(schedule ifc.Schedule) error {
 	_, err := db.connectionPool.Exec(context.Background(),
-		"INSERT INTO schedule("+rowNames+") VALUES "+sqlParamsRange(14),
+		"INSERT INTO schedule("+rowNames+") VALUES "+sqlParamsRange(44),
 		schedule.Name,
 		schedule.Enabled,
 		schedule.Status,
@@ -189,6 +431,36 @@ func (db PostgresDB) Insert(schedule ifc.Schedule) error {
 		schedule.CorrelationID,
 		schedule.TaskToDomain,
 		schedule.LastUpdate,
+		schedule.MisfirePolicy,
+		schedule.MisfireMaxCount,
+		schedule.LastFireTime,
+		schedule.TimeZone,
+		schedule.CronFormat,
+		schedule.Condition,
+		schedule.ConditionMessage,
+		schedule.MaxRunDuration,
+		schedule.OnOverrun,
+		schedule.RetryPolicy,
+		schedule.OnFailure,
+		schedule.OnFailureMaxCount,
+		schedule.ConcurrencyPolicy,
+		schedule.MaxConcurrentRuns,
+		schedule.TriggerMode,
+		schedule.DependsOn,
+		schedule.OutputMapping,
+		schedule.UpdateContextOnFailure,
+		schedule.MaxContextBytes,
+		schedule.IncludeCalendars,
+		schedule.ExcludeCalendars,
+		schedule.JitterSeconds,
+		schedule.JitterMode,
+		schedule.PausedBy,
+		schedule.PauseReason,
+		schedule.PausedAt,
+		schedule.PausedUntil,
+		labelsOrEmpty(schedule.Labels),
+		schedule.NextFireTime,
+		schedule.EnabledAt,
 	)
 	return err
 }
@@ -207,6 +479,38 @@ func (db PostgresDB) UpdateStatusAndWorkflowContext(schedule ifc.Schedule) error
 	return err
 }
 
+func (db PostgresDB) UpdateLastFireTime(scheduleName string, lastFireTime time.Time) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"UPDATE schedule SET last_fire_time=$2 WHERE schedule_name=$1",
+		scheduleName, lastFireTime)
+	return err
+}
+
+func (db PostgresDB) UpdateNextFireTime(scheduleName string, nextFireTime *time.Time) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"UPDATE schedule SET next_fire_time=$2 WHERE schedule_name=$1",
+		scheduleName, nextFireTime)
+	return err
+}
+
+func (db PostgresDB) UpdateCondition(scheduleName string, condition string, conditionMessage string) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"UPDATE schedule SET schedule_condition=$2, condition_message=$3 WHERE schedule_name=$1",
+		scheduleName, condition, conditionMessage)
+	return err
+}
+
+func (db PostgresDB) ResumePause(scheduleName string, pausedUntil time.Time, enabledAt time.Time) (bool, error) {
+	result, err := db.connectionPool.Exec(context.Background(),
+		`UPDATE schedule SET is_enabled=true, enabled_at=$3, paused_by='', pause_reason='', paused_at=NULL, paused_until=NULL
+			WHERE schedule_name=$1 AND NOT is_enabled AND paused_until=$2`,
+		scheduleName, pausedUntil, enabledAt)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
 func (db PostgresDB) Update(schedule ifc.Schedule) error {
 	_, err := db.connectionPool.Exec(context.Background(),
 		`UPDATE schedule SET
@@ -222,7 +526,37 @@ func (db PostgresDB) Update(schedule ifc.Schedule) error {
 			to_date=$11,
 			correlation_id=$12,
 			task_to_domain=$13,
-			last_update=$14
+			last_update=$14,
+			misfire_policy=$15,
+			misfire_max_count=$16,
+			last_fire_time=$17,
+			time_zone=$18,
+			cron_format=$19,
+			schedule_condition=$20,
+			condition_message=$21,
+			max_run_duration=$22,
+			on_overrun=$23,
+			retry_policy=$24,
+			on_failure=$25,
+			on_failure_max_count=$26,
+			concurrency_policy=$27,
+			max_concurrent_runs=$28,
+			trigger_mode=$29,
+			depends_on=$30,
+			output_mapping=$31,
+			update_context_on_failure=$32,
+			max_context_bytes=$33,
+			include_calendars=$34,
+			exclude_calendars=$35,
+			jitter_seconds=$36,
+			jitter_mode=$37,
+			paused_by=$38,
+			pause_reason=$39,
+			paused_at=$40,
+			paused_until=$41,
+			labels=$42,
+			next_fire_time=$43,
+			enabled_at=$44
 			WHERE schedule_name=$1`,
 		schedule.Name,
 		schedule.Enabled,
@@ -238,6 +572,36 @@ func (db PostgresDB) Update(schedule ifc.Schedule) error {
 		schedule.CorrelationID,
 		schedule.TaskToDomain,
 		schedule.LastUpdate,
+		schedule.MisfirePolicy,
+		schedule.MisfireMaxCount,
+		schedule.LastFireTime,
+		schedule.TimeZone,
+		schedule.CronFormat,
+		schedule.Condition,
+		schedule.ConditionMessage,
+		schedule.MaxRunDuration,
+		schedule.OnOverrun,
+		schedule.RetryPolicy,
+		schedule.OnFailure,
+		schedule.OnFailureMaxCount,
+		schedule.ConcurrencyPolicy,
+		schedule.MaxConcurrentRuns,
+		schedule.TriggerMode,
+		schedule.DependsOn,
+		schedule.OutputMapping,
+		schedule.UpdateContextOnFailure,
+		schedule.MaxContextBytes,
+		schedule.IncludeCalendars,
+		schedule.ExcludeCalendars,
+		schedule.JitterSeconds,
+		schedule.JitterMode,
+		schedule.PausedBy,
+		schedule.PauseReason,
+		schedule.PausedAt,
+		schedule.PausedUntil,
+		labelsOrEmpty(schedule.Labels),
+		schedule.NextFireTime,
+		schedule.EnabledAt,
 	)
 	return err
 }
@@ -248,6 +612,339 @@ func (db PostgresDB) RemoveByName(scheduleName string) error {
 	return err
 }
 
+func (db PostgresDB) queryExecutions(sql string, args ...interface{}) ([]ifc.Execution, error) {
+	rows, err := db.connectionPool.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	executions := make([]ifc.Execution, 0)
+	for rows.Next() {
+		var execution ifc.Execution
+		err = rows.Scan(&execution.ID, &execution.ScheduleName, &execution.FireTime,
+			&execution.Trigger, &execution.WorkflowID, &execution.Status, &execution.StartTime,
+			&execution.EndTime, &execution.Error, &execution.Attempts, &execution.Recoveries,
+			&execution.WorkflowName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, execution)
+	}
+	return executions, nil
+}
+
+const executionRowNames = `
+execution_id,
+schedule_name,
+fire_time,
+trigger_source,
+workflow_id,
+execution_status,
+start_time,
+end_time,
+error_message,
+attempts,
+recoveries,
+workflow_name`
+
+// Creates WHERE clause (including the keyword) and its arguments from the filter.
+func executionFilterClause(filter ifc.ExecutionFilter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	if filter.ScheduleName != "" {
+		args = append(args, filter.ScheduleName)
+		conditions = append(conditions, fmt.Sprintf("schedule_name=$%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("execution_status=$%d", len(args)))
+	}
+	if filter.FiredBefore != nil {
+		args = append(args, *filter.FiredBefore)
+		conditions = append(conditions, fmt.Sprintf("fire_time<$%d", len(args)))
+	}
+	if filter.ExcludeStatus != "" {
+		args = append(args, filter.ExcludeStatus)
+		conditions = append(conditions, fmt.Sprintf("execution_status<>$%d", len(args)))
+	}
+	if filter.WorkflowName != "" {
+		args = append(args, filter.WorkflowName)
+		conditions = append(conditions, fmt.Sprintf("workflow_name=$%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (db PostgresDB) InsertExecution(execution ifc.Execution) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"INSERT INTO execution("+executionRowNames+") VALUES "+sqlParamsRange(12),
+		execution.ID,
+		execution.ScheduleName,
+		execution.FireTime,
+		execution.Trigger,
+		execution.WorkflowID,
+		execution.Status,
+		execution.StartTime,
+		execution.EndTime,
+		execution.Error,
+		execution.Attempts,
+		execution.Recoveries,
+		execution.WorkflowName,
+	)
+	return err
+}
+
+func (db PostgresDB) UpdateExecution(execution ifc.Execution) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		`UPDATE execution SET
+			workflow_id=$2,
+			execution_status=$3,
+			start_time=$4,
+			end_time=$5,
+			error_message=$6,
+			attempts=$7,
+			recoveries=$8,
+			workflow_name=$9
+			WHERE execution_id=$1`,
+		execution.ID,
+		execution.WorkflowID,
+		execution.Status,
+		execution.StartTime,
+		execution.EndTime,
+		execution.Error,
+		execution.Attempts,
+		execution.Recoveries,
+		execution.WorkflowName,
+	)
+	return err
+}
+
+func (db PostgresDB) FindExecutionByWorkflowID(workflowID string) (*ifc.Execution, error) {
+	executions, err := db.queryExecutions("SELECT "+executionRowNames+" FROM execution WHERE workflow_id=$1",
+		workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if len(executions) == 1 {
+		return &executions[0], nil
+	} else if len(executions) == 0 {
+		return nil, nil
+	}
+	return nil, errors.New(
+		fmt.Sprintf(
+			"Unexpected result for FindExecutionByWorkflowID('%s'): Found %d items",
+			workflowID, len(executions),
+		))
+}
+
+func (db PostgresDB) FindExecutions(filter ifc.ExecutionFilter, after string, limit int) ([]ifc.Execution, error) {
+	where, args := executionFilterClause(filter)
+	if after != "" {
+		args = append(args, after)
+		cursor := fmt.Sprintf("(fire_time, execution_id) < (SELECT fire_time, execution_id FROM execution WHERE execution_id=$%d)", len(args))
+		if where == "" {
+			where = " WHERE " + cursor
+		} else {
+			where = where + " AND " + cursor
+		}
+	}
+	sql := "SELECT " + executionRowNames + " FROM execution" + where + " ORDER BY fire_time DESC, execution_id DESC"
+	if limit > 0 {
+		args = append(args, limit)
+		sql = fmt.Sprintf("%s LIMIT $%d", sql, len(args))
+	}
+	return db.queryExecutions(sql, args...)
+}
+
+func (db PostgresDB) CountExecutions(filter ifc.ExecutionFilter) (int, error) {
+	where, args := executionFilterClause(filter)
+	var count int
+	err := db.connectionPool.QueryRow(context.Background(),
+		"SELECT count(*) FROM execution"+where, args...).Scan(&count)
+	return count, err
+}
+
+func (db PostgresDB) AcquireLease(leaseName string, holder string, ttl time.Duration) (bool, error) {
+	// database clock is used so that replicas with skewed clocks agree on expiration
+	result, err := db.connectionPool.Exec(context.Background(),
+		`INSERT INTO lease(lease_name, holder, expires_at) VALUES ($1, $2, now() + $3 * interval '1 millisecond')
+			ON CONFLICT (lease_name) DO UPDATE SET holder=EXCLUDED.holder, expires_at=EXCLUDED.expires_at
+			WHERE lease.holder=EXCLUDED.holder OR lease.expires_at < now()`,
+		leaseName, holder, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (db PostgresDB) ReleaseLease(leaseName string, holder string) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"DELETE FROM lease WHERE lease_name=$1 AND holder=$2", leaseName, holder)
+	return err
+}
+
+const contextVersionRowNames = `
+schedule_name,
+version,
+workflow_context,
+source,
+workflow_id,
+created`
+
+func (db PostgresDB) InsertContextVersion(version ifc.ContextVersion, keep int) error {
+	tx, err := db.connectionPool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+	// version number is assigned here, the one in the argument is ignored
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO context_version(`+contextVersionRowNames+`)
+			SELECT $1, coalesce(max(version), 0) + 1, $2, $3, $4, $5 FROM context_version WHERE schedule_name=$1`,
+		version.ScheduleName, version.WorkflowContext, version.Source, version.WorkflowID, version.Created)
+	if err != nil {
+		return err
+	}
+	if keep > 0 {
+		_, err = tx.Exec(context.Background(),
+			`DELETE FROM context_version WHERE schedule_name=$1 AND version <=
+				(SELECT max(version) FROM context_version WHERE schedule_name=$1) - $2`,
+			version.ScheduleName, keep)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+func (db PostgresDB) queryContextVersions(sql string, args ...interface{}) ([]ifc.ContextVersion, error) {
+	rows, err := db.connectionPool.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]ifc.ContextVersion, 0)
+	for rows.Next() {
+		var version ifc.ContextVersion
+		err = rows.Scan(&version.ScheduleName, &version.Version, &version.WorkflowContext,
+			&version.Source, &version.WorkflowID, &version.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (db PostgresDB) FindContextVersions(scheduleName string, limit int) ([]ifc.ContextVersion, error) {
+	sql := "SELECT " + contextVersionRowNames + " FROM context_version WHERE schedule_name=$1 ORDER BY version DESC"
+	if limit > 0 {
+		return db.queryContextVersions(sql+" LIMIT $2", scheduleName, limit)
+	}
+	return db.queryContextVersions(sql, scheduleName)
+}
+
+func (db PostgresDB) FindContextVersion(scheduleName string, version int) (*ifc.ContextVersion, error) {
+	versions, err := db.queryContextVersions("SELECT "+contextVersionRowNames+" FROM context_version WHERE schedule_name=$1 AND version=$2",
+		scheduleName, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return &versions[0], nil
+}
+
+const calendarRowNames = `
+calendar_name,
+description,
+time_zone,
+date_ranges,
+weekly_windows,
+last_update`
+
+func (db PostgresDB) queryCalendars(sql string, args ...interface{}) ([]ifc.Calendar, error) {
+	rows, err := db.connectionPool.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	calendars := make([]ifc.Calendar, 0)
+	for rows.Next() {
+		var calendar ifc.Calendar
+		err = rows.Scan(&calendar.Name, &calendar.Description, &calendar.TimeZone,
+			&calendar.DateRanges, &calendar.WeeklyWindows, &calendar.LastUpdate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
+
+func (db PostgresDB) FindAllCalendars() ([]ifc.Calendar, error) {
+	return db.queryCalendars("SELECT " + calendarRowNames + " FROM calendar ORDER BY calendar_name ASC")
+}
+
+func (db PostgresDB) FindCalendarByName(calendarName string) (*ifc.Calendar, error) {
+	calendars, err := db.queryCalendars("SELECT "+calendarRowNames+" FROM calendar WHERE calendar_name=$1",
+		calendarName)
+	if err != nil {
+		return nil, err
+	}
+	if len(calendars) == 0 {
+		return nil, nil
+	}
+	return &calendars[0], nil
+}
+
+func (db PostgresDB) InsertCalendar(calendar ifc.Calendar) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"INSERT INTO calendar("+calendarRowNames+") VALUES "+sqlParamsRange(6),
+		calendar.Name,
+		calendar.Description,
+		calendar.TimeZone,
+		calendar.DateRanges,
+		calendar.WeeklyWindows,
+		calendar.LastUpdate,
+	)
+	return err
+}
+
+func (db PostgresDB) UpdateCalendar(calendar ifc.Calendar) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		`UPDATE calendar SET
+			description=$2,
+			time_zone=$3,
+			date_ranges=$4,
+			weekly_windows=$5,
+			last_update=$6
+			WHERE calendar_name=$1`,
+		calendar.Name,
+		calendar.Description,
+		calendar.TimeZone,
+		calendar.DateRanges,
+		calendar.WeeklyWindows,
+		calendar.LastUpdate,
+	)
+	return err
+}
+
+func (db PostgresDB) RemoveCalendarByName(calendarName string) error {
+	_, err := db.connectionPool.Exec(context.Background(),
+		"DELETE FROM calendar WHERE calendar_name=$1", calendarName)
+	return err
+}
+
 // Creates string with sql parameters.
 // Example: sqlParamsRange(3) returns "($1,$2,$3)"
 func sqlParamsRange(max uint) string {
@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/frinx/schellar/ifc"
 	"github.com/frinx/schellar/it"
@@ -15,6 +17,59 @@ func TestSqlParamsRange(t *testing.T) {
 	}
 }
 
+func TestScheduleFilterClause(t *testing.T) {
+	selector, err := ifc.ParseLabelSelector("team=netops,region notin (eu,us),!deprecated")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	where, args := scheduleFilterClause(ifc.ScheduleFilter{WorkflowName: "backup", LabelSelector: selector})
+	expected := " WHERE workflow_name=$1 AND (labels @> $2) AND NOT (labels @> $3 OR labels @> $4) AND NOT labels ? $5"
+	if where != expected {
+		t.Fatalf("Unexpected: %v, should be %v", where, expected)
+	}
+	expectedArgs := []interface{}{"backup", map[string]string{"team": "netops"},
+		map[string]string{"region": "eu"}, map[string]string{"region": "us"}, "deprecated"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Unexpected args: %v", args)
+	}
+	where, args = scheduleFilterClause(ifc.ScheduleFilter{})
+	if where != "" || len(args) != 0 {
+		t.Fatalf("Unexpected empty filter: %v %v", where, args)
+	}
+
+	enabled := true
+	now := time.Now()
+	where, args = scheduleFilterClause(ifc.ScheduleFilter{NamePrefix: "net_", NameContains: "50%", Enabled: &enabled, ActiveAt: &now})
+	expected = " WHERE schedule_name LIKE $1 AND schedule_name ILIKE $2 AND is_enabled=$3" +
+		" AND (from_date IS NULL OR from_date<$4) AND (to_date IS NULL OR to_date>$4)"
+	if where != expected {
+		t.Fatalf("Unexpected: %v, should be %v", where, expected)
+	}
+	expectedArgs = []interface{}{`net\_%`, `%50\%%`, true, now}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Unexpected args: %v", args)
+	}
+}
+
+func TestScheduleOrderClause(t *testing.T) {
+	cases := map[string]ifc.ScheduleFilter{
+		" ORDER BY schedule_name ASC":                                 {},
+		" ORDER BY schedule_name DESC":                                {SortBy: ifc.ScheduleSortName, SortDescending: true},
+		" ORDER BY next_fire_time ASC NULLS LAST, schedule_name ASC":  {SortBy: ifc.ScheduleSortNextRun},
+		" ORDER BY last_fire_time DESC NULLS LAST, schedule_name ASC": {SortBy: ifc.ScheduleSortLastRun, SortDescending: true},
+	}
+	for expected, filter := range cases {
+		orderBy, err := scheduleOrderClause(filter)
+		if err != nil || orderBy != expected {
+			t.Fatalf("Unexpected: %v, should be %v. err=%v", orderBy, expected, err)
+		}
+	}
+	_, err := scheduleOrderClause(ifc.ScheduleFilter{SortBy: "UNKNOWN"})
+	if err == nil {
+		t.Fatalf("Expected error of unknown sort order")
+	}
+}
+
 func initIntegration(t *testing.T) ifc.DB {
 	db := InitDB()
 	it.ExpectTableSize(db, 0, "before test", t)
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// LoadCalendars returns calendars included or excluded by the schedule by their names.
+// Calendars that do not exist are left out.
+func LoadCalendars(schedule *ifc.Schedule) (map[string]ifc.Calendar, error) {
+	calendars := make(map[string]ifc.Calendar)
+	for _, name := range schedule.Calendars() {
+		if _, loaded := calendars[name]; loaded {
+			continue
+		}
+		calendar, err := Configuration.Db.FindCalendarByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if calendar != nil {
+			calendars[name] = *calendar
+		}
+	}
+	return calendars, nil
+}
+
+// calendarSkipReason returns why calendars of the schedule forbid launching a workflow at t, empty string if they do not
+func calendarSkipReason(schedule *ifc.Schedule, t time.Time) (string, error) {
+	if len(schedule.IncludeCalendars) == 0 && len(schedule.ExcludeCalendars) == 0 {
+		return "", nil
+	}
+	calendars, err := LoadCalendars(schedule)
+	if err != nil {
+		return "", fmt.Errorf("Error loading calendars of schedule %s. err=%s", schedule.Name, err)
+	}
+	return schedule.CheckCalendars(t, calendars), nil
+}
+
+// recordSkip stores SKIPPED execution with the reason why the trigger did not launch a workflow
+func recordSkip(scheduleName string, fireTime time.Time, trigger string, reason string) {
+	now := time.Now()
+	calendarSkipsCounter.WithLabelValues(scheduleName).Inc()
+	err := Configuration.Db.InsertExecution(ifc.Execution{
+		ID:           uuid.NewString(),
+		ScheduleName: scheduleName,
+		FireTime:     fireTime,
+		Trigger:      trigger,
+		Status:       "SKIPPED",
+		EndTime:      &now,
+		Error:        reason,
+	})
+	if err != nil {
+		logrus.Errorf("Error saving skipped execution of schedule %s. err=%s", scheduleName, err)
+	}
+}
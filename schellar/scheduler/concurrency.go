@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxQueuedTriggers limits how many triggers of a schedule with QUEUE concurrency policy may wait for launch
+const maxQueuedTriggers = 100
+
+// ErrTriggerQueued is returned when the trigger was deferred until a running workflow of the schedule completes
+var ErrTriggerQueued = fmt.Errorf("%w: trigger queued", ErrTriggerSkipped)
+
+// applyConcurrencyPolicy decides whether a new workflow of the schedule may be launched while running workflows
+// have not finished yet. Returns (wrapped) ErrTriggerSkipped or ErrTriggerQueued when it must not be launched now.
+// Manual triggers are never queued, they are skipped instead.
+func applyConcurrencyPolicy(schedule *ifc.Schedule, execution ifc.Execution, running []ifc.Execution) error {
+	limit := schedule.ConcurrencyLimit()
+	if limit == 0 || len(running) < limit {
+		if len(running) > 0 {
+			logrus.Infof("Schedule %s: Launching concurrent workflow (%s). count=%d", schedule.Name, schedule.WorkflowName, len(running))
+		}
+		return nil
+	}
+
+	switch schedule.Concurrency() {
+	case ifc.ConcurrencyReplace:
+		// executions are ordered from the most recent one, the oldest ones are replaced
+		for _, replaced := range running[limit-1:] {
+			if replaced.Status == "RETRYING" {
+				concurrencyActionsCounter.WithLabelValues(schedule.Name, "SKIPPED").Inc()
+				return fmt.Errorf("%w: schedule %s cannot replace trigger from %s, its launch is being retried",
+					ErrTriggerSkipped, schedule.Name, replaced.FireTime)
+			}
+			err := replaceExecution(schedule, replaced)
+			if err != nil {
+				return fmt.Errorf("%w: schedule %s could not replace workflow id (%s). err=%s",
+					ErrTriggerSkipped, schedule.Name, replaced.WorkflowID, err)
+			}
+		}
+		return nil
+	case ifc.ConcurrencyQueue:
+		if execution.Trigger != TriggerManual {
+			return queueTrigger(schedule, execution)
+		}
+	}
+	concurrencyActionsCounter.WithLabelValues(schedule.Name, "SKIPPED").Inc()
+	return fmt.Errorf("%w: schedule %s previous workflow id (%s) has not finished yet", ErrTriggerSkipped, schedule.Name, running[0].WorkflowID)
+}
+
+// replaceExecution terminates running workflow to make room for a new one
+func replaceExecution(schedule *ifc.Schedule, execution ifc.Execution) error {
+	reason := fmt.Sprintf("Replaced by a new workflow of schedule %s", schedule.Name)
+	err := terminateWorkflow(execution.WorkflowID, reason)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Schedule %s: Workflow %s terminated, replaced by a new one", schedule.Name, execution.WorkflowID)
+	concurrencyActionsCounter.WithLabelValues(schedule.Name, "REPLACED").Inc()
+
+	wf, err := getWorkflowInstance(execution.WorkflowID)
+	if err != nil {
+		logrus.Errorf("Could not get terminated workflow instance. err=%s", err)
+		wf = map[string]interface{}{
+			"workflowId":            execution.WorkflowID,
+			"status":                "TERMINATED",
+			"reasonForIncompletion": reason,
+		}
+	}
+	recordCompletion(wf)
+	return nil
+}
+
+// queueTrigger records the trigger as QUEUED execution, launched later by CheckRunningWorkflows.
+// Execution already persisted (e.g. PENDING one) is updated, QUEUED one stays in the queue.
+func queueTrigger(schedule *ifc.Schedule, execution ifc.Execution) error {
+	if execution.Status == "QUEUED" {
+		return fmt.Errorf("%w: schedule %s has %d running workflow(s)", ErrTriggerQueued, schedule.Name, schedule.ConcurrencyLimit())
+	}
+	queued, err := Configuration.Db.CountExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name, Status: "QUEUED"})
+	if err != nil {
+		return fmt.Errorf("Error counting queued triggers. err=%s", err)
+	}
+	if queued >= maxQueuedTriggers {
+		concurrencyActionsCounter.WithLabelValues(schedule.Name, "SKIPPED").Inc()
+		return fmt.Errorf("%w: schedule %s has too many queued triggers. count=%d", ErrTriggerSkipped, schedule.Name, queued)
+	}
+	execution.ScheduleName = schedule.Name
+	execution.WorkflowName = schedule.WorkflowName
+	execution.Status = "QUEUED"
+	if execution.ID == "" {
+		execution.ID = uuid.NewString()
+		err = Configuration.Db.InsertExecution(execution)
+	} else {
+		err = Configuration.Db.UpdateExecution(execution)
+	}
+	if err != nil {
+		return fmt.Errorf("Error saving queued trigger. err=%s", err)
+	}
+	concurrencyActionsCounter.WithLabelValues(schedule.Name, "QUEUED").Inc()
+	return fmt.Errorf("%w: schedule %s has %d running workflow(s). queued=%d", ErrTriggerQueued, schedule.Name, schedule.ConcurrencyLimit(), queued+1)
+}
+
+// launchQueuedTriggers launches the oldest queued triggers of the schedule while running workflows are below its limit.
+// Queued triggers are checked and launched like any other trigger once they are allowed by concurrency policy,
+// those not allowed by the schedule anymore are skipped. Returns numbers of launched workflows and retried launches.
+func launchQueuedTriggers(schedule ifc.Schedule, running int) (int, int) {
+	limit := schedule.ConcurrencyLimit()
+	if !schedule.Enabled || running >= limit {
+		return 0, 0
+	}
+	queued, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{ScheduleName: schedule.Name, Status: "QUEUED"}, "", 0)
+	if err != nil {
+		logrus.Errorf("Error finding queued triggers of schedule %s. err=%s", schedule.Name, err)
+		return 0, 0
+	}
+
+	launched := 0
+	retrying := 0
+	// executions are ordered from the most recent one
+	for i := len(queued) - 1; i >= 0 && running+launched+retrying < limit; i-- {
+		execution := queued[i]
+		// schedule, its calendars or dependencies may have changed since the trigger was queued
+		runningExecutions, err := checkTrigger(&schedule, execution, false)
+		if errors.Is(err, ErrTriggerQueued) {
+			break
+		}
+		if errors.Is(err, ErrTriggerSkipped) {
+			skipWaitingTrigger(execution, err.Error())
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("Error checking queued trigger of schedule %s. err=%s", schedule.Name, err)
+			break
+		}
+		release := tryLaunchSlot(schedule.WorkflowName)
+		if release == nil {
+			logrus.Debugf("Schedule %s: Queued triggers wait for launch limits", schedule.Name)
+			break
+		}
+		logrus.Infof("Schedule %s: Launching queued trigger from %s", schedule.Name, execution.FireTime)
+		_, err = launchExecution(&schedule, execution, len(runningExecutions), nil, release)
+		if errors.Is(err, ErrLaunchRetrying) {
+			logrus.Debugf("%s", err)
+			retrying++
+		} else if err != nil {
+			logrus.Errorf("Error launching queued trigger of schedule %s. err=%s", schedule.Name, err)
+		} else {
+			launched++
+		}
+	}
+	return launched, retrying
+}
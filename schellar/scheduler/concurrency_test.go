@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+)
+
+func TestFireScheduleConcurrencyPolicy(t *testing.T) {
+	cases := []struct {
+		policy             string
+		maxConcurrentRuns  int
+		expectedErr        error
+		expectedLaunches   int
+		expectedTerminated int
+		// expected executions by workflowId/trigger
+		expectedExecutions map[string]string
+	}{
+		{ifc.ConcurrencyAllow, 0, nil, 1, 0,
+			map[string]string{"wf-old/TIMER": "RUNNING", "wf-1/TIMER": "RUNNING"}},
+		{ifc.ConcurrencyAllow, 1, ErrTriggerSkipped, 0, 0,
+			map[string]string{"wf-old/TIMER": "RUNNING"}},
+		{ifc.ConcurrencyForbid, 0, ErrTriggerSkipped, 0, 0,
+			map[string]string{"wf-old/TIMER": "RUNNING"}},
+		{ifc.ConcurrencyReplace, 0, nil, 1, 1,
+			map[string]string{"wf-old/TIMER": "TERMINATED", "wf-1/TIMER": "RUNNING"}},
+		{ifc.ConcurrencyQueue, 0, ErrTriggerQueued, 0, 0,
+			map[string]string{"wf-old/TIMER": "RUNNING", "/TIMER": "QUEUED"}},
+		{ifc.ConcurrencyQueue, 2, nil, 1, 0,
+			map[string]string{"wf-old/TIMER": "RUNNING", "wf-1/TIMER": "RUNNING"}},
+	}
+	for _, c := range cases {
+		t.Run(c.policy, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			setupTest(t, db, conductor.ServeHTTP)
+			schedule := newTestSchedule("concurrency")
+			schedule.ConcurrencyPolicy = c.policy
+			schedule.MaxConcurrentRuns = c.maxConcurrentRuns
+			db.Insert(schedule)
+			addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-time.Minute))
+
+			_, err := FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, false)
+			if !errors.Is(err, c.expectedErr) {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if conductor.launchCount() != c.expectedLaunches || len(conductor.terminated) != c.expectedTerminated {
+				t.Errorf("Unexpected launched=%v terminated=%v", conductor.launched, conductor.terminated)
+			}
+			statuses := executionStatuses(db, schedule.Name)
+			if len(statuses) != len(c.expectedExecutions) {
+				t.Fatalf("Unexpected executions %v", statuses)
+			}
+			for key, status := range c.expectedExecutions {
+				if statuses[key] != status {
+					t.Errorf("Unexpected executions %v", statuses)
+				}
+			}
+		})
+	}
+}
+
+func TestFireScheduleIgnoresConcurrencyPolicy(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	schedule := newTestSchedule("forbid")
+	schedule.ConcurrencyPolicy = ifc.ConcurrencyForbid
+	db.Insert(schedule)
+	addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-time.Minute))
+
+	_, err := FireSchedule(schedule.Name, time.Now(), TriggerManual, nil, true)
+	if err != nil || conductor.launchCount() != 1 {
+		t.Fatalf("Expected manual launch ignoring concurrency policy. err=%v launched=%v", err, conductor.launched)
+	}
+}
+
+func TestQueuedTriggerLaunchedWhenRunningWorkflowCompletes(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	schedule := newTestSchedule("queue")
+	schedule.ConcurrencyPolicy = ifc.ConcurrencyQueue
+	db.Insert(schedule)
+	addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-time.Minute))
+
+	for i := 0; i < 2; i++ {
+		_, err := FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, false)
+		if !errors.Is(err, ErrTriggerQueued) {
+			t.Fatalf("Expected queued trigger, got %v", err)
+		}
+	}
+
+	// queued triggers wait while the workflow is running
+	refreshSchedule(db.schedule(schedule.Name))
+	if conductor.launchCount() != 0 {
+		t.Fatalf("Queued trigger launched while workflow is running %v", conductor.launched)
+	}
+
+	conductor.finish("wf-old", "COMPLETED", nil)
+	refreshSchedule(db.schedule(schedule.Name))
+	if conductor.launchCount() != 1 {
+		t.Fatalf("Expected one queued trigger launched, got %v", conductor.launched)
+	}
+	executions := db.executionsOf(schedule.Name)
+	if executions[1].WorkflowID != "wf-1" || executions[1].Status != "RUNNING" || executions[2].Status != "QUEUED" {
+		t.Errorf("Expected the oldest queued trigger launched first, got %+v", executions)
+	}
+	if db.schedule(schedule.Name).Status != "RUNNING" {
+		t.Errorf("Unexpected schedule status %s", db.schedule(schedule.Name).Status)
+	}
+}
+
+func TestLaunchQueuedTriggersChecksSchedule(t *testing.T) {
+	fireTime := time.Now().Add(-time.Hour)
+	toDate := time.Now().Add(-time.Minute)
+	retryPolicy := ifc.RetryPolicy{MaxAttempts: 2, InitialDelaySeconds: 1, Multiplier: 1, MaxDelaySeconds: 1}
+	cases := map[string]struct {
+		update           func(*ifc.Schedule)
+		launchStatuses   []int
+		expectedStatus   string
+		expectedLaunched int
+		expectedRetrying int
+	}{
+		"allowed":       {func(*ifc.Schedule) {}, nil, "RUNNING", 1, 0},
+		"paused":        {func(schedule *ifc.Schedule) { schedule.Enabled = false }, nil, "QUEUED", 0, 0},
+		"toDate passed": {func(schedule *ifc.Schedule) { schedule.ToDate = &toDate }, nil, "SKIPPED", 0, 0},
+		"dependency not satisfied": {func(schedule *ifc.Schedule) {
+			schedule.DependsOn = []ifc.Dependency{{ScheduleName: "upstream", Condition: ifc.DependencyCompleted}}
+		}, nil, "SKIPPED", 0, 0},
+		"launch retried": {func(schedule *ifc.Schedule) { schedule.RetryPolicy = retryPolicy },
+			[]int{http.StatusServiceUnavailable}, "RETRYING", 0, 1},
+		"launch rejected": {func(schedule *ifc.Schedule) { schedule.RetryPolicy = retryPolicy },
+			[]int{http.StatusBadRequest}, "FAILED", 0, 0},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			conductor.launchStatuses = c.launchStatuses
+			setupTest(t, db, conductor.ServeHTTP)
+			schedule := newTestSchedule("queue")
+			schedule.ConcurrencyPolicy = ifc.ConcurrencyQueue
+			c.update(&schedule)
+			db.Insert(schedule)
+			db.InsertExecution(ifc.Execution{
+				ID:           uuid.NewString(),
+				ScheduleName: schedule.Name,
+				FireTime:     fireTime,
+				Trigger:      TriggerTimer,
+				Status:       "QUEUED",
+				WorkflowName: "workflow",
+			})
+
+			launched, retrying := launchQueuedTriggers(schedule, 0)
+			if launched != c.expectedLaunched || retrying != c.expectedRetrying || conductor.launchCount() != c.expectedLaunched {
+				t.Errorf("Unexpected launched=%d retrying=%d workflows=%v", launched, retrying, conductor.launched)
+			}
+			execution := db.executionsOf(schedule.Name)[0]
+			if execution.Status != c.expectedStatus {
+				t.Errorf("Unexpected status %s of queued execution. err=%s", execution.Status, execution.Error)
+			}
+			waitFor(t, func() bool { return !isRetrying(execution.ID) })
+		})
+	}
+}
@@ -7,28 +7,35 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
+	"github.com/frinx/schellar/ifc"
 	"github.com/sirupsen/logrus"
 )
 
-func launchWorkflow(scheduleName string) error {
-	logrus.Debugf("startWorkflow scheduleName=%s", scheduleName)
+// launchWorkflow starts new workflow instance of the schedule fired at fireTime. Templates in the schedule
+// workflow context are rendered for the run and values of inputOverride replace its top level keys,
+// without modifying the stored schedule.
+func launchWorkflow(schedule *ifc.Schedule, fireTime time.Time, trigger string, inputOverride map[string]interface{}) (string, error) {
+	logrus.Debugf("startWorkflow scheduleName=%s", schedule.Name)
 
-	logrus.Debugf("Loading schedule definitions from DB")
-
-	schedule, err := Configuration.Db.FindByName(scheduleName)
+	templateData, err := newTemplateData(schedule, fireTime, trigger)
+	if err != nil {
+		return "", err
+	}
+	input, err := ifc.RenderContext(schedule.WorkflowContext, templateData)
 	if err != nil {
-		logrus.Errorf("Couldn't find schedule %s", scheduleName)
-		return err
+		return "", fmt.Errorf("%w. err=%s", ErrInvalidTemplate, err)
 	}
+	for key, value := range inputOverride {
+		input[key] = value
+	}
+	input["scheduleName"] = schedule.Name
 
 	wf := make(map[string]interface{})
 	wf["name"] = schedule.WorkflowName
 	wf["version"] = schedule.WorkflowVersion
-	wf["input"] = schedule.WorkflowContext
-	wf["input"].(map[string]interface{})["scheduleName"] = schedule.Name
+	wf["input"] = input
 	if len(schedule.CorrelationID) > 0 {
 		wf["correlationId"] = schedule.CorrelationID
 	}
@@ -42,22 +49,31 @@ func launchWorkflow(scheduleName string) error {
 	resp, data, err := postHTTP(url, wfb, Configuration.AdminGroups, Configuration.AdminRoles, Configuration.From)
 	if err != nil {
 		logrus.Errorf("Call to Conductor POST /workflow failed. err=%s", err)
-		return err
+		return "", err
 	}
 	if resp.StatusCode != 200 {
 		logrus.Warnf("POST /workflow call status!=200. resp=%v", resp)
-		return fmt.Errorf("Failed to create new workflow instance. status=%d", resp.StatusCode)
+		return "", &conductorStatusError{StatusCode: resp.StatusCode, Message: "Failed to create new workflow instance"}
 	}
-	logrus.Infof("Schedule %s: Workflow %s launched. workflowId=%s", schedule.Name, schedule.WorkflowName, string(data))
-	return nil
+	workflowID := string(data)
+	logrus.Infof("Schedule %s: Workflow %s launched. workflowId=%s", schedule.Name, schedule.WorkflowName, workflowID)
+	return workflowID, nil
 }
 
+// getWorkflow returns workflow definition, nil if it does not exist. Empty version means the latest one.
 func getWorkflow(name string, version string) (map[string]interface{}, error) {
 	logrus.Debugf("getWorkflow %s", name)
-	resp, data, err := getHTTP(fmt.Sprintf("%s/metadata/workflow/%s?version=%s", Configuration.ConductorURL, name, version), Configuration.AdminGroups, Configuration.AdminRoles, Configuration.From)
+	metadataURL := fmt.Sprintf("%s/metadata/workflow/%s", Configuration.ConductorURL, url.PathEscape(name))
+	if version != "" {
+		metadataURL = fmt.Sprintf("%s?version=%s", metadataURL, url.QueryEscape(version))
+	}
+	resp, data, err := getHTTP(metadataURL, Configuration.AdminGroups, Configuration.AdminRoles, Configuration.From)
 	if err != nil {
 		return nil, fmt.Errorf("GET /metadata/workflow/name failed. err=%s", err)
 	}
+	if resp.StatusCode == 404 {
+		return nil, nil
+	}
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("Couldn't get workflow info. name=%s", name)
 	}
@@ -88,38 +104,33 @@ func getWorkflowInstance(workflowID string) (map[string]interface{}, error) {
 	return wfdata, nil
 }
 
-func findWorkflows(workflowType string, scheduleName string, running bool) (map[string]interface{}, error) {
-	logrus.Debugf("findWorkflows(workflowType=%s,scheduleName=%s,running=%v)", workflowType, scheduleName, running)
-	// escape : -> \:
-
-	workflowType = strings.ReplaceAll(workflowType, ":", "\\:")
-	scheduleName = strings.ReplaceAll(scheduleName, ":", "\\:")
-	runstr := "status IN 'RUNNING'"
-	if !running {
-		runstr = "status IN 'FAILED,COMPLETED,TERMINATED,TIMED_OUT,PAUSED'"
-	}
-
-	query := fmt.Sprintf("workflowType='%s' AND %s", workflowType, runstr)
-
-	logrus.Debugf("search query=%s", query)
-	sr := fmt.Sprintf("%s/workflow/search?query=%s&sort=endTime:DESC&size=5", Configuration.ConductorURL, url.PathEscape(query))
-	resp, data, err := getHTTP(sr, Configuration.AdminGroups, Configuration.AdminRoles, Configuration.From)
-
+// terminateWorkflow terminates running workflow instance, reason is stored by Conductor as reasonForIncompletion
+func terminateWorkflow(workflowID string, reason string) error {
+	logrus.Debugf("terminateWorkflow %s", workflowID)
+	terminateURL := fmt.Sprintf("%s/workflow/%s?reason=%s", Configuration.ConductorURL, url.PathEscape(workflowID), url.QueryEscape(reason))
+	resp, _, err := deleteHTTP(terminateURL, Configuration.AdminGroups, Configuration.AdminRoles, Configuration.From)
 	if err != nil {
-		return nil, fmt.Errorf("GET /workflow/search failed. err=%s", err)
+		return fmt.Errorf("DELETE /workflow/%s failed. err=%s", workflowID, err)
 	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("GET /workflow/search failed. status=%d. err=%s", resp.StatusCode, err)
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return fmt.Errorf("Couldn't terminate workflow. workflowId=%s. status=%d", workflowID, resp.StatusCode)
 	}
+	return nil
+}
 
-	var wfdata map[string]interface{}
-	err = json.Unmarshal(data, &wfdata)
+// rerunWorkflow asks Conductor to retry the last failed task (action "retry")
+// or to restart the whole workflow (action "restart") of a finished workflow instance
+func rerunWorkflow(workflowID string, action string) error {
+	logrus.Debugf("rerunWorkflow %s %s", action, workflowID)
+	rerunURL := fmt.Sprintf("%s/workflow/%s/%s", Configuration.ConductorURL, url.PathEscape(workflowID), action)
+	resp, _, err := postHTTP(rerunURL, []byte{}, Configuration.AdminGroups, Configuration.AdminRoles, Configuration.From)
 	if err != nil {
-		logrus.Errorf("Error parsing json. err=%s", err)
-		return nil, err
+		return fmt.Errorf("POST /workflow/%s/%s failed. err=%s", workflowID, action, err)
 	}
-	return wfdata, nil
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return &conductorStatusError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("Couldn't %s workflow %s", action, workflowID)}
+	}
+	return nil
 }
 
 func postHTTP(url string, data []byte, groupHeader string, roleHeaders string, fromHeader string) (http.Response, []byte, error) {
@@ -175,3 +186,29 @@ func getHTTP(url0 string, groupHeader string, roleHeaders string, fromHeader str
 	logrus.Debugf("Response body: %s", datar)
 	return *response, datar, nil
 }
+
+func deleteHTTP(url0 string, groupHeader string, roleHeaders string, fromHeader string) (http.Response, []byte, error) {
+	req, err := http.NewRequest("DELETE", url0, nil)
+	if err != nil {
+		logrus.Errorf("HTTP request creation failed. err=%s", err)
+		return http.Response{}, []byte{}, err
+	}
+
+	req.Header.Set("x-auth-user-groups", groupHeader)
+	req.Header.Set("x-auth-user-roles", roleHeaders)
+	req.Header.Set("from", fromHeader)
+
+	client := &http.Client{
+		Timeout: time.Second * 10,
+	}
+	logrus.Debugf("DELETE request=%v", req)
+	response, err1 := client.Do(req)
+	if err1 != nil {
+		logrus.Errorf("HTTP request invocation failed. err=%s", err1)
+		return http.Response{}, []byte{}, err1
+	}
+
+	datar, _ := ioutil.ReadAll(response.Body)
+	logrus.Debugf("Response body: %s", datar)
+	return *response, datar, nil
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// fakeConductor serves workflow API of Conductor from memory
+type fakeConductor struct {
+	mutex     sync.Mutex
+	workflows map[string]map[string]interface{}
+	// launched holds ids of started workflows in order
+	launched   []string
+	terminated []string
+	reruns     []string
+	// launchStatuses are returned by the next POST /workflow calls instead of starting a workflow
+	launchStatuses []int
+}
+
+func newFakeConductor() *fakeConductor {
+	return &fakeConductor{workflows: make(map[string]map[string]interface{})}
+}
+
+// addWorkflow makes a workflow with the status known to Conductor
+func (conductor *fakeConductor) addWorkflow(workflowID string, status string) {
+	conductor.mutex.Lock()
+	defer conductor.mutex.Unlock()
+	conductor.workflows[workflowID] = map[string]interface{}{"workflowId": workflowID, "status": status}
+}
+
+// finish changes status and output of a workflow
+func (conductor *fakeConductor) finish(workflowID string, status string, output map[string]interface{}) {
+	conductor.mutex.Lock()
+	defer conductor.mutex.Unlock()
+	conductor.workflows[workflowID]["status"] = status
+	conductor.workflows[workflowID]["output"] = output
+}
+
+func (conductor *fakeConductor) status(workflowID string) string {
+	conductor.mutex.Lock()
+	defer conductor.mutex.Unlock()
+	return conductor.workflows[workflowID]["status"].(string)
+}
+
+func (conductor *fakeConductor) launchCount() int {
+	conductor.mutex.Lock()
+	defer conductor.mutex.Unlock()
+	return len(conductor.launched)
+}
+
+func (conductor *fakeConductor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conductor.mutex.Lock()
+	defer conductor.mutex.Unlock()
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/workflow"), "/")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/workflow":
+		if len(conductor.launchStatuses) > 0 {
+			status := conductor.launchStatuses[0]
+			conductor.launchStatuses = conductor.launchStatuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+		var workflow map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&workflow)
+		workflowID := fmt.Sprintf("wf-%d", len(conductor.launched)+1)
+		conductor.launched = append(conductor.launched, workflowID)
+		conductor.workflows[workflowID] = map[string]interface{}{
+			"workflowId":   workflowID,
+			"workflowType": workflow["name"],
+			"status":       "RUNNING",
+			"input":        workflow["input"],
+		}
+		w.Write([]byte(workflowID))
+	case r.Method == http.MethodGet && len(path) == 2:
+		workflow, exists := conductor.workflows[path[1]]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(workflow)
+	case r.Method == http.MethodDelete && len(path) == 2:
+		conductor.terminated = append(conductor.terminated, path[1])
+		conductor.workflows[path[1]]["status"] = "TERMINATED"
+		conductor.workflows[path[1]]["reasonForIncompletion"] = r.URL.Query().Get("reason")
+	case r.Method == http.MethodPost && len(path) == 3:
+		conductor.reruns = append(conductor.reruns, path[2]+" "+path[1])
+		conductor.workflows[path[1]]["status"] = "RUNNING"
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/frinx/schellar/ifc"
 	"github.com/frinx/schellar/mongo"
@@ -13,15 +14,27 @@ import (
 
 var Configuration Config
 
-func init() {
+// InitConfiguration reads configuration from ENV and connects to the backend, it has to be called before starting the scheduler
+func InitConfiguration() {
 	log.Println("Init configuration from ENV")
 	Configuration = Config{
-		Db:                   dbConf(),
-		CheckIntervalSeconds: intervalConf(),
-		ConductorURL:         conductorUrlConf(),
-		AdminRoles:           conductorAdminRolesHeadersConf(),
-		AdminGroups:          conductorAdminGroupHeadersConf(),
-		From:                 "schellar",
+		Db:                       dbConf(),
+		CheckIntervalSeconds:     intervalConf(),
+		ConductorURL:             conductorUrlConf(),
+		AdminRoles:               conductorAdminRolesHeadersConf(),
+		AdminGroups:              conductorAdminGroupHeadersConf(),
+		From:                     "schellar",
+		HAEnabled:                haEnabledConf(),
+		LeaseSeconds:             leaseSecondsConf(),
+		InstanceID:               instanceIDConf(),
+		ValidateWorkflows:        validateWorkflowsConf(),
+		NotificationURL:          notificationUrlConf(),
+		EventsEnabled:            eventsEnabledConf(),
+		EventsSecret:             eventsSecretConf(),
+		ReconcileIntervalSeconds: reconcileIntervalConf(),
+		LaunchRateLimit:          launchRateLimitConf(),
+		LaunchMaxInFlight:        launchMaxInFlightConf(),
+		LaunchWorkflowLimits:     launchWorkflowLimitsConf(),
 	}
 }
 
@@ -32,6 +45,22 @@ type Config struct {
 	AdminRoles           string
 	AdminGroups          string
 	From                 string
+	HAEnabled            bool
+	LeaseSeconds         int
+	InstanceID           string
+	ValidateWorkflows    bool
+	NotificationURL      string
+	// EventsEnabled accepts workflow events, polling of running workflows is then done every ReconcileIntervalSeconds
+	EventsEnabled bool
+	// EventsSecret (if not empty) is required as bearer token of workflow events received over HTTP
+	EventsSecret             string
+	ReconcileIntervalSeconds int
+	// LaunchRateLimit is maximum number of workflow launches per second, 0 means no limit
+	LaunchRateLimit float64
+	// LaunchMaxInFlight is maximum number of workflow launches in progress at the same time, 0 means no limit
+	LaunchMaxInFlight int
+	// LaunchWorkflowLimits caps running workflows by workflow name across all schedules
+	LaunchWorkflowLimits map[string]int
 }
 
 func conductorUrlConf() string {
@@ -40,6 +69,12 @@ func conductorUrlConf() string {
 	return conductorURL
 }
 
+func notificationUrlConf() string {
+	notificationURL := ifc.GetEnvOrDefault("NOTIFICATION_URL", "")
+	logrus.Infof("NOTIFICATION_URL=%s", notificationURL)
+	return notificationURL
+}
+
 func conductorAdminGroupHeadersConf() string {
 	conductorAdminGroups := ifc.GetEnvOrDefault("ADMIN_GROUPS", "network-admin")
 	logrus.Infof("ADMIN_GROUPS=%s", conductorAdminGroups)
@@ -64,6 +99,124 @@ func intervalConf() int {
 	return checkIntervalSeconds
 }
 
+func eventsEnabledConf() bool {
+	eventsEnabledString := ifc.GetEnvOrDefault("EVENTS_ENABLED", "false")
+	eventsEnabled, err := strconv.ParseBool(eventsEnabledString)
+	if err != nil {
+		logrus.Fatalf("Canot parse EVENTS_ENABLED value '%s'. Error: %v", eventsEnabledString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("EVENTS_ENABLED=%v", eventsEnabled)
+	return eventsEnabled
+}
+
+func eventsSecretConf() string {
+	eventsSecret := ifc.GetEnvOrDefault("EVENTS_SECRET", "")
+	logrus.Infof("EVENTS_SECRET configured=%v", eventsSecret != "")
+	return eventsSecret
+}
+
+func reconcileIntervalConf() int {
+	reconcileIntervalString := ifc.GetEnvOrDefault("RECONCILE_INTERVAL_SECONDS", "300")
+	reconcileIntervalSeconds, err := strconv.Atoi(reconcileIntervalString)
+	if err != nil {
+		logrus.Fatalf("Canot parse RECONCILE_INTERVAL_SECONDS value '%s'. Error: %v", reconcileIntervalString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("RECONCILE_INTERVAL_SECONDS=%d", reconcileIntervalSeconds)
+	return reconcileIntervalSeconds
+}
+
+func launchRateLimitConf() float64 {
+	launchRateLimitString := ifc.GetEnvOrDefault("LAUNCH_RATE_LIMIT", "0")
+	launchRateLimit, err := strconv.ParseFloat(launchRateLimitString, 64)
+	if err != nil || launchRateLimit < 0 {
+		logrus.Fatalf("Canot parse LAUNCH_RATE_LIMIT value '%s'. Error: %v", launchRateLimitString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("LAUNCH_RATE_LIMIT=%v", launchRateLimit)
+	return launchRateLimit
+}
+
+func launchMaxInFlightConf() int {
+	launchMaxInFlightString := ifc.GetEnvOrDefault("LAUNCH_MAX_IN_FLIGHT", "0")
+	launchMaxInFlight, err := strconv.Atoi(launchMaxInFlightString)
+	if err != nil || launchMaxInFlight < 0 {
+		logrus.Fatalf("Canot parse LAUNCH_MAX_IN_FLIGHT value '%s'. Error: %v", launchMaxInFlightString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("LAUNCH_MAX_IN_FLIGHT=%d", launchMaxInFlight)
+	return launchMaxInFlight
+}
+
+// launchWorkflowLimitsConf parses comma separated workflowName=limit pairs
+func launchWorkflowLimitsConf() map[string]int {
+	launchWorkflowLimitsString := ifc.GetEnvOrDefault("LAUNCH_WORKFLOW_LIMITS", "")
+	launchWorkflowLimits := make(map[string]int)
+	for _, pair := range strings.Split(launchWorkflowLimitsString, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		separator := strings.LastIndex(pair, "=")
+		if separator <= 0 {
+			logrus.Fatalf("Canot parse LAUNCH_WORKFLOW_LIMITS value '%s'. Error: expected workflowName=limit", launchWorkflowLimitsString)
+			os.Exit(1)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(pair[separator+1:]))
+		if err != nil || limit <= 0 {
+			logrus.Fatalf("Canot parse LAUNCH_WORKFLOW_LIMITS value '%s'. Error: %v", launchWorkflowLimitsString, err)
+			os.Exit(1)
+		}
+		launchWorkflowLimits[strings.TrimSpace(pair[:separator])] = limit
+	}
+	logrus.Infof("LAUNCH_WORKFLOW_LIMITS=%v", launchWorkflowLimits)
+	return launchWorkflowLimits
+}
+
+func haEnabledConf() bool {
+	haEnabledString := ifc.GetEnvOrDefault("HA_ENABLED", "false")
+	haEnabled, err := strconv.ParseBool(haEnabledString)
+	if err != nil {
+		logrus.Fatalf("Canot parse HA_ENABLED value '%s'. Error: %v", haEnabledString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("HA_ENABLED=%v", haEnabled)
+	return haEnabled
+}
+
+func leaseSecondsConf() int {
+	leaseSecondsString := ifc.GetEnvOrDefault("HA_LEASE_SECONDS", "30")
+	leaseSeconds, err := strconv.Atoi(leaseSecondsString)
+	if err != nil || leaseSeconds <= 0 {
+		logrus.Fatalf("Canot parse HA_LEASE_SECONDS value '%s'. Error: %v", leaseSecondsString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("HA_LEASE_SECONDS=%d", leaseSeconds)
+	return leaseSeconds
+}
+
+func instanceIDConf() string {
+	hostname, _ := os.Hostname()
+	instanceID := ifc.GetEnvOrDefault("HA_INSTANCE_ID", hostname)
+	if instanceID == "" {
+		logrus.Fatalf("'HA_INSTANCE_ID' is required when hostname is not available")
+		os.Exit(1)
+	}
+	logrus.Infof("HA_INSTANCE_ID=%s", instanceID)
+	return instanceID
+}
+
+func validateWorkflowsConf() bool {
+	validateWorkflowsString := ifc.GetEnvOrDefault("VALIDATE_WORKFLOWS", "true")
+	validateWorkflows, err := strconv.ParseBool(validateWorkflowsString)
+	if err != nil {
+		logrus.Fatalf("Canot parse VALIDATE_WORKFLOWS value '%s'. Error: %v", validateWorkflowsString, err)
+		os.Exit(1)
+	}
+	logrus.Infof("VALIDATE_WORKFLOWS=%v", validateWorkflows)
+	return validateWorkflows
+}
+
 func dbConf() ifc.DB {
 
 	backend := ifc.GetEnvOrDefault("BACKEND", "postgres")
@@ -0,0 +1,381 @@
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+// fakeDB keeps schedules, executions and the leader lease in memory
+type fakeDB struct {
+	mutex      sync.Mutex
+	schedules  map[string]ifc.Schedule
+	executions []ifc.Execution
+	calendars  map[string]ifc.Calendar
+	versions   []ifc.ContextVersion
+	// leaseHolder holds the leader lease, leaseErr fails AcquireLease
+	leaseHolder string
+	leaseErr    error
+	// lastFireTimeUpdates records names of schedules whose last fire time was updated
+	lastFireTimeUpdates []string
+	// countBlock (if not nil) blocks CountExecutions until closed
+	countBlock chan struct{}
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		schedules: make(map[string]ifc.Schedule),
+		calendars: make(map[string]ifc.Calendar),
+	}
+}
+
+// setupTest points the scheduler to db and Conductor served by handler (if not nil) until the test ends
+func setupTest(t *testing.T, db *fakeDB, handler http.HandlerFunc) {
+	previous := Configuration
+	Configuration = Config{
+		Db:                   db,
+		CheckIntervalSeconds: 1,
+		From:                 "schellar",
+		HAEnabled:            true,
+		LeaseSeconds:         30,
+		InstanceID:           "test",
+		LaunchWorkflowLimits: make(map[string]int),
+	}
+	limiter = newLaunchLimiter()
+	if handler != nil {
+		conductor := httptest.NewServer(handler)
+		t.Cleanup(conductor.Close)
+		Configuration.ConductorURL = conductor.URL
+	}
+	leaderMutex.Lock()
+	leader = true
+	leaderMutex.Unlock()
+	t.Cleanup(func() {
+		leaderMutex.Lock()
+		leader = false
+		leaderMutex.Unlock()
+		Configuration = previous
+	})
+}
+
+func (db *fakeDB) schedule(name string) ifc.Schedule {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	return db.schedules[name]
+}
+
+// executionsOf returns executions of the schedule in the order they were inserted
+func (db *fakeDB) executionsOf(scheduleName string) []ifc.Execution {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	executions := make([]ifc.Execution, 0)
+	for _, execution := range db.executions {
+		if execution.ScheduleName == scheduleName {
+			executions = append(executions, execution)
+		}
+	}
+	return executions
+}
+
+func (db *fakeDB) findSchedules(match func(ifc.Schedule) bool) []ifc.Schedule {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	schedules := make([]ifc.Schedule, 0)
+	for _, schedule := range db.schedules {
+		if match(schedule) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name < schedules[j].Name })
+	return schedules
+}
+
+func (db *fakeDB) updateSchedule(name string, update func(*ifc.Schedule)) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	schedule, exists := db.schedules[name]
+	if !exists {
+		return errors.New("not found")
+	}
+	update(&schedule)
+	db.schedules[name] = schedule
+	return nil
+}
+
+func (db *fakeDB) updateExecution(workflowID string, update func(*ifc.Execution)) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for i := range db.executions {
+		if db.executions[i].WorkflowID == workflowID {
+			update(&db.executions[i])
+		}
+	}
+}
+
+func (db *fakeDB) FindAll() ([]ifc.Schedule, error) {
+	return db.findSchedules(func(ifc.Schedule) bool { return true }), nil
+}
+
+func (db *fakeDB) FindAllByWorkflowType(workflowName string, workflowId string) ([]ifc.Schedule, error) {
+	return db.findSchedules(func(schedule ifc.Schedule) bool { return schedule.WorkflowName == workflowName }), nil
+}
+
+func (db *fakeDB) FindAllByEnabled(enabled bool) ([]ifc.Schedule, error) {
+	return db.findSchedules(func(schedule ifc.Schedule) bool { return schedule.Enabled == enabled }), nil
+}
+
+func (db *fakeDB) FindByName(scheduleName string) (*ifc.Schedule, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	schedule, exists := db.schedules[scheduleName]
+	if !exists {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+func (db *fakeDB) FindByStatus(status string) ([]ifc.Schedule, error) {
+	return db.findSchedules(func(schedule ifc.Schedule) bool { return schedule.Status == status }), nil
+}
+
+func (db *fakeDB) FindSchedules(filter ifc.ScheduleFilter) ([]ifc.Schedule, error) {
+	return db.findSchedules(func(schedule ifc.Schedule) bool {
+		return (filter.WorkflowName == "" || schedule.WorkflowName == filter.WorkflowName) &&
+			(filter.Status == "" || schedule.Status == filter.Status) &&
+			(filter.Enabled == nil || schedule.Enabled == *filter.Enabled) &&
+			strings.HasPrefix(schedule.Name, filter.NamePrefix) &&
+			filter.LabelSelector.Matches(schedule.Labels)
+	}), nil
+}
+
+func (db *fakeDB) UpdateStatus(scheduleName string, scheduleStatus string) error {
+	return db.updateSchedule(scheduleName, func(schedule *ifc.Schedule) { schedule.Status = scheduleStatus })
+}
+
+func (db *fakeDB) UpdateStatusAndWorkflowContext(updated ifc.Schedule) error {
+	return db.updateSchedule(updated.Name, func(schedule *ifc.Schedule) {
+		schedule.Status = updated.Status
+		schedule.WorkflowContext = updated.WorkflowContext
+	})
+}
+
+func (db *fakeDB) UpdateLastFireTime(scheduleName string, lastFireTime time.Time) error {
+	db.mutex.Lock()
+	db.lastFireTimeUpdates = append(db.lastFireTimeUpdates, scheduleName)
+	db.mutex.Unlock()
+	return db.updateSchedule(scheduleName, func(schedule *ifc.Schedule) { schedule.LastFireTime = &lastFireTime })
+}
+
+func (db *fakeDB) UpdateNextFireTime(scheduleName string, nextFireTime *time.Time) error {
+	return db.updateSchedule(scheduleName, func(schedule *ifc.Schedule) { schedule.NextFireTime = nextFireTime })
+}
+
+func (db *fakeDB) UpdateCondition(scheduleName string, condition string, conditionMessage string) error {
+	return db.updateSchedule(scheduleName, func(schedule *ifc.Schedule) {
+		schedule.Condition = condition
+		schedule.ConditionMessage = conditionMessage
+	})
+}
+
+func (db *fakeDB) ResumePause(scheduleName string, pausedUntil time.Time, enabledAt time.Time) (bool, error) {
+	resumed := false
+	err := db.updateSchedule(scheduleName, func(schedule *ifc.Schedule) {
+		if schedule.Enabled || schedule.PausedUntil == nil || !schedule.PausedUntil.Equal(pausedUntil) {
+			return
+		}
+		schedule.Resume(enabledAt)
+		resumed = true
+	})
+	return resumed, err
+}
+
+func (db *fakeDB) Insert(schedule ifc.Schedule) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.schedules[schedule.Name] = schedule
+	return nil
+}
+
+func (db *fakeDB) Update(schedule ifc.Schedule) error {
+	return db.updateSchedule(schedule.Name, func(stored *ifc.Schedule) { *stored = schedule })
+}
+
+func (db *fakeDB) RemoveByName(scheduleName string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	delete(db.schedules, scheduleName)
+	return nil
+}
+
+func (db *fakeDB) InsertExecution(execution ifc.Execution) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.executions = append(db.executions, execution)
+	return nil
+}
+
+func (db *fakeDB) UpdateExecution(execution ifc.Execution) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for i := range db.executions {
+		if db.executions[i].ID == execution.ID {
+			db.executions[i] = execution
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (db *fakeDB) FindExecutionByWorkflowID(workflowID string) (*ifc.Execution, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for _, execution := range db.executions {
+		if execution.WorkflowID == workflowID {
+			return &execution, nil
+		}
+	}
+	return nil, nil
+}
+
+func matchesExecution(filter ifc.ExecutionFilter, execution ifc.Execution) bool {
+	return (filter.ScheduleName == "" || execution.ScheduleName == filter.ScheduleName) &&
+		(filter.Status == "" || execution.Status == filter.Status) &&
+		(filter.ExcludeStatus == "" || execution.Status != filter.ExcludeStatus) &&
+		(filter.WorkflowName == "" || execution.WorkflowName == filter.WorkflowName) &&
+		(filter.FiredBefore == nil || execution.FireTime.Before(*filter.FiredBefore))
+}
+
+func (db *fakeDB) FindExecutions(filter ifc.ExecutionFilter, after string, limit int) ([]ifc.Execution, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	executions := make([]ifc.Execution, 0)
+	for _, execution := range db.executions {
+		if matchesExecution(filter, execution) {
+			executions = append(executions, execution)
+		}
+	}
+	sort.SliceStable(executions, func(i, j int) bool {
+		if executions[i].FireTime.Equal(executions[j].FireTime) {
+			return executions[i].ID > executions[j].ID
+		}
+		return executions[i].FireTime.After(executions[j].FireTime)
+	})
+	if after != "" {
+		for i, execution := range executions {
+			if execution.ID == after {
+				executions = executions[i+1:]
+				break
+			}
+		}
+	}
+	if limit > 0 && len(executions) > limit {
+		executions = executions[:limit]
+	}
+	return executions, nil
+}
+
+func (db *fakeDB) CountExecutions(filter ifc.ExecutionFilter) (int, error) {
+	if db.countBlock != nil {
+		<-db.countBlock
+	}
+	executions, err := db.FindExecutions(filter, "", 0)
+	return len(executions), err
+}
+
+func (db *fakeDB) AcquireLease(leaseName string, holder string, ttl time.Duration) (bool, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	if db.leaseErr != nil {
+		return false, db.leaseErr
+	}
+	if db.leaseHolder != "" && db.leaseHolder != holder {
+		return false, nil
+	}
+	db.leaseHolder = holder
+	return true, nil
+}
+
+func (db *fakeDB) ReleaseLease(leaseName string, holder string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	if db.leaseHolder == holder {
+		db.leaseHolder = ""
+	}
+	return nil
+}
+
+func (db *fakeDB) InsertContextVersion(version ifc.ContextVersion, keep int) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	version.Version = len(db.versions) + 1
+	db.versions = append(db.versions, version)
+	return nil
+}
+
+func (db *fakeDB) FindContextVersions(scheduleName string, limit int) ([]ifc.ContextVersion, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	versions := make([]ifc.ContextVersion, 0)
+	for i := len(db.versions) - 1; i >= 0 && (limit == 0 || len(versions) < limit); i-- {
+		if db.versions[i].ScheduleName == scheduleName {
+			versions = append(versions, db.versions[i])
+		}
+	}
+	return versions, nil
+}
+
+func (db *fakeDB) FindContextVersion(scheduleName string, version int) (*ifc.ContextVersion, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for _, contextVersion := range db.versions {
+		if contextVersion.ScheduleName == scheduleName && contextVersion.Version == version {
+			return &contextVersion, nil
+		}
+	}
+	return nil, nil
+}
+
+func (db *fakeDB) FindAllCalendars() ([]ifc.Calendar, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	calendars := make([]ifc.Calendar, 0)
+	for _, calendar := range db.calendars {
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
+
+func (db *fakeDB) FindCalendarByName(calendarName string) (*ifc.Calendar, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	calendar, exists := db.calendars[calendarName]
+	if !exists {
+		return nil, nil
+	}
+	return &calendar, nil
+}
+
+func (db *fakeDB) InsertCalendar(calendar ifc.Calendar) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.calendars[calendar.Name] = calendar
+	return nil
+}
+
+func (db *fakeDB) UpdateCalendar(calendar ifc.Calendar) error {
+	return db.InsertCalendar(calendar)
+}
+
+func (db *fakeDB) RemoveCalendarByName(calendarName string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	delete(db.calendars, calendarName)
+	return nil
+}
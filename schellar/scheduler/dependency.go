@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/sirupsen/logrus"
+)
+
+// executionsPageSize limits executions loaded at once while looking for the latest finished one
+const executionsPageSize = 50
+
+// checkDependencies returns error describing the first dependency of the schedule that is not satisfied
+func checkDependencies(schedule *ifc.Schedule, now time.Time) error {
+	for _, dependency := range schedule.DependsOn {
+		latest, err := latestFinishedExecution(dependency.ScheduleName)
+		if err != nil {
+			return fmt.Errorf("Error finding executions of schedule %s. err=%s", dependency.ScheduleName, err)
+		}
+		err = dependency.SatisfiedBy(latest, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// latestFinishedExecution returns the most recent execution of the schedule in a terminal status, nil if there is none
+func latestFinishedExecution(scheduleName string) (*ifc.Execution, error) {
+	after := ""
+	for {
+		executions, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{ScheduleName: scheduleName}, after, executionsPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, execution := range executions {
+			if ifc.IsTerminalStatus(execution.Status) {
+				return &execution, nil
+			}
+		}
+		if len(executions) < executionsPageSize {
+			return nil, nil
+		}
+		after = executions[len(executions)-1].ID
+	}
+}
+
+// triggerDownstream fires enabled schedules with UPSTREAM trigger mode depending on the schedule whose workflow just finished
+func triggerDownstream(upstreamName string) {
+	schedules, err := Configuration.Db.FindAllByEnabled(true)
+	if err != nil {
+		logrus.Errorf("Error finding schedules depending on %s. err=%s", upstreamName, err)
+		return
+	}
+	for _, schedule := range schedules {
+		if !schedule.IsUpstreamTriggered() || !schedule.DependsOnSchedule(upstreamName) {
+			continue
+		}
+		logrus.Infof("Schedule %s: Upstream schedule %s finished", schedule.Name, upstreamName)
+		go func(scheduleName string) {
+			_, err := FireSchedule(scheduleName, time.Now(), TriggerUpstream, nil, false)
+			if errors.Is(err, ErrTriggerSkipped) {
+				logrus.Debugf("%s", err)
+			} else if err != nil {
+				logrus.Errorf("Error processing upstream trigger of schedule %s. err=%s", scheduleName, err)
+			}
+		}(schedule.Name)
+	}
+}
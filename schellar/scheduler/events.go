@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WorkflowEventSource notifies the scheduler about Conductor workflows that changed their state, e.g. a webhook
+// called by Conductor workflow status listener or a consumer of Conductor event queue.
+// Sources run only on the leader, so that the events are processed by the replica checking running workflows.
+type WorkflowEventSource interface {
+	// Run passes ids of changed workflows to handle until stop is closed
+	Run(handle func(workflowID string), stop <-chan struct{})
+}
+
+var (
+	eventSources      []WorkflowEventSource
+	eventSourcesMutex sync.Mutex
+)
+
+// RegisterWorkflowEventSource adds the source started when this replica becomes the leader
+func RegisterWorkflowEventSource(source WorkflowEventSource) {
+	eventSourcesMutex.Lock()
+	defer eventSourcesMutex.Unlock()
+	eventSources = append(eventSources, source)
+}
+
+func startEventSources(stop <-chan struct{}) {
+	eventSourcesMutex.Lock()
+	defer eventSourcesMutex.Unlock()
+	for _, source := range eventSources {
+		go source.Run(HandleWorkflowEvent, stop)
+	}
+}
+
+// HandleWorkflowEvent refreshes the schedule that launched the workflow right away instead of waiting for the next check.
+// Workflow state is always read from Conductor, the event only tells which schedule to refresh.
+func HandleWorkflowEvent(workflowID string) {
+	execution, err := Configuration.Db.FindExecutionByWorkflowID(workflowID)
+	if err != nil {
+		logrus.Errorf("Error getting execution of workflow %s. err=%s", workflowID, err)
+		workflowEventsCounter.WithLabelValues("ERROR").Inc()
+		return
+	}
+	if execution == nil {
+		logrus.Debugf("Ignoring event of workflow %s not launched by a schedule", workflowID)
+		workflowEventsCounter.WithLabelValues("IGNORED").Inc()
+		return
+	}
+	if execution.Status != "RUNNING" {
+		logrus.Debugf("Ignoring event of workflow %s already recorded as %s", workflowID, execution.Status)
+		workflowEventsCounter.WithLabelValues("IGNORED").Inc()
+		return
+	}
+	schedule, err := Configuration.Db.FindByName(execution.ScheduleName)
+	if err != nil || schedule == nil {
+		logrus.Errorf("Couldn't get schedule %s of workflow %s. err=%v", execution.ScheduleName, workflowID, err)
+		workflowEventsCounter.WithLabelValues("ERROR").Inc()
+		return
+	}
+	logrus.Debugf("Refreshing schedule %s on event of workflow %s", schedule.Name, workflowID)
+	refreshSchedule(*schedule)
+	workflowEventsCounter.WithLabelValues("PROCESSED").Inc()
+}
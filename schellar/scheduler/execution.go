@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// recordLaunch stores execution of a workflow launched (or failed to launch) by a schedule for the fire time
+// and trigger of execution. Execution without id is a new one, PENDING execution is updated.
+func recordLaunch(schedule *ifc.Schedule, execution ifc.Execution, workflowID string, attempts int, launchErr error) {
+	now := time.Now()
+	execution.ScheduleName = schedule.Name
+	execution.WorkflowName = schedule.WorkflowName
+	execution.WorkflowID = workflowID
+	execution.Status = "RUNNING"
+	execution.StartTime = &now
+	execution.Attempts = attempts
+	if launchErr != nil {
+		execution.Status = "FAILED"
+		execution.EndTime = &now
+		execution.Error = launchErr.Error()
+	}
+	var err error
+	if execution.ID == "" {
+		execution.ID = uuid.NewString()
+		err = Configuration.Db.InsertExecution(execution)
+	} else {
+		err = Configuration.Db.UpdateExecution(execution)
+	}
+	if err != nil {
+		logrus.Errorf("Error saving execution of schedule %s. err=%s", schedule.Name, err)
+	}
+}
+
+// finishLaunch records the launched (or failed) workflow in the execution and updates status of the schedule
+func finishLaunch(schedule *ifc.Schedule, execution ifc.Execution, running int, workflowID string, attempts int, launchErr error) error {
+	recordLaunch(schedule, execution, workflowID, attempts, launchErr)
+	return updateLaunchStatus(schedule.Name, running, attempts, launchErr)
+}
+
+// updateLaunchStatus marks the schedule RUNNING after its workflow was launched. When the launch failed
+// and no other workflow of the schedule is running, the schedule is marked FAILED and the launch error returned.
+func updateLaunchStatus(scheduleName string, running int, attempts int, launchErr error) error {
+	if launchErr != nil {
+		launchFailuresCounter.WithLabelValues(scheduleName).Inc()
+		if running == 0 {
+			// nothing else is tracked for the schedule, so the failed launch is its latest result
+			statusErr := Configuration.Db.UpdateStatus(scheduleName, "FAILED")
+			if statusErr != nil {
+				logrus.Errorf("Error saving Schedule status err=%s", statusErr)
+			}
+		}
+		return fmt.Errorf("Error launching Workflow. attempts=%d. err=%s", attempts, launchErr)
+	}
+
+	logrus.Debugf("Updating Schedule status. name=%s. status=%s", scheduleName, "RUNNING")
+	err := Configuration.Db.UpdateStatus(scheduleName, "RUNNING")
+	if err != nil {
+		logrus.Errorf("Error saving Schedule status err=%s", err)
+	}
+	return nil
+}
+
+// recordCompletion updates the execution of a finished Conductor workflow instance.
+// Returns the updated execution, nil if the workflow was not launched by schellar.
+func recordCompletion(workflow map[string]interface{}) *ifc.Execution {
+	workflowID := GetStringValue(workflow, "workflowId", "")
+	if workflowID == "" {
+		return nil
+	}
+	execution, err := Configuration.Db.FindExecutionByWorkflowID(workflowID)
+	if err != nil {
+		logrus.Errorf("Error getting execution of workflow %s. err=%s", workflowID, err)
+		return nil
+	}
+	if execution == nil {
+		logrus.Debugf("No execution recorded for workflow %s", workflowID)
+		return nil
+	}
+	status := GetStringValue(workflow, "status", execution.Status)
+	if status == execution.Status && execution.EndTime != nil {
+		return execution
+	}
+	execution.Status = status
+	execution.StartTime = getTimeValue(workflow, "startTime", execution.StartTime)
+	execution.EndTime = getTimeValue(workflow, "endTime", execution.EndTime)
+	execution.Error = GetStringValue(workflow, "reasonForIncompletion", execution.Error)
+	err = Configuration.Db.UpdateExecution(*execution)
+	if err != nil {
+		logrus.Errorf("Error updating execution of workflow %s. err=%s", workflowID, err)
+	}
+	return execution
+}
+
+// getTimeValue reads Conductor epoch milliseconds timestamp
+func getTimeValue(m map[string]interface{}, keyName string, defaultValue *time.Time) *time.Time {
+	v, exists := m[keyName]
+	if !exists {
+		return defaultValue
+	}
+	millis, ok := v.(float64)
+	if !ok || millis <= 0 {
+		return defaultValue
+	}
+	t := time.UnixMilli(int64(millis))
+	return &t
+}
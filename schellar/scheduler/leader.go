@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const leaseName = "schellar-leader"
+
+var (
+	leader      bool
+	leaderMutex sync.Mutex
+	stopChecker chan struct{}
+)
+
+// IsLeader returns true if this replica runs schedule timers and checks running workflows.
+// Without HA mode the only replica is always the leader.
+func IsLeader() bool {
+	leaderMutex.Lock()
+	defer leaderMutex.Unlock()
+	return leader
+}
+
+func becomeLeader() error {
+	leaderMutex.Lock()
+	if leader {
+		leaderMutex.Unlock()
+		return nil
+	}
+	leader = true
+	stopChecker = make(chan struct{})
+	leaderMutex.Unlock()
+
+	logrus.Infof("Instance %s: Became leader", Configuration.InstanceID)
+	leaderGauge.Set(1)
+	leaderTransitionsCounter.Inc()
+	go CheckRunningWorkflows(stopChecker)
+	startEventSources(stopChecker)
+	if !Configuration.HAEnabled {
+		// the leader in HA mode prepares timers on every lease renewal
+		go refreshTimers(stopChecker)
+	}
+	if launchLimitsEnabled() {
+		// also launches triggers left pending by the previous leader
+		go dispatchPendingLaunches(stopChecker)
+	}
+	err := failInterruptedRetries()
+	if err != nil {
+		logrus.Errorf("%s", err)
+	}
+	// missed fire times are computed before timers start, so that no trigger is fired twice
+	err = catchUpMissedRuns()
+	if err != nil {
+		logrus.Errorf("Error catching up missed timer triggers. err=%s", err)
+	}
+	return refreshSchedules()
+}
+
+func stepDown() {
+	leaderMutex.Lock()
+	if !leader {
+		leaderMutex.Unlock()
+		return
+	}
+	leader = false
+	close(stopChecker)
+	leaderMutex.Unlock()
+
+	logrus.Infof("Instance %s: Lost leadership", Configuration.InstanceID)
+	leaderGauge.Set(0)
+	leaderTransitionsCounter.Inc()
+	err := PrepareTimers()
+	if err != nil {
+		logrus.Errorf("Error stopping timers. err=%s", err)
+	}
+}
+
+// runLeaderElection periodically renews the lease in the DB, replicas that cannot acquire it stay
+// on standby until the lease of the current leader expires
+func runLeaderElection() {
+	ttl := time.Duration(Configuration.LeaseSeconds) * time.Second
+	lastRenewal := time.Time{}
+	for {
+		lastRenewal = renewLeadership(ttl, lastRenewal)
+		time.Sleep(ttl / 3)
+	}
+}
+
+// renewLeadership acquires or renews the lease once, this replica becomes the leader or steps down accordingly.
+// Returns time of the last successful renewal.
+func renewLeadership(ttl time.Duration, lastRenewal time.Time) time.Time {
+	acquired, err := Configuration.Db.AcquireLease(leaseName, Configuration.InstanceID, ttl)
+	if err != nil {
+		logrus.Errorf("Error acquiring leader lease. err=%s", err)
+		// our lease may have been taken over meanwhile
+		if time.Since(lastRenewal) >= ttl {
+			stepDown()
+		}
+		return lastRenewal
+	}
+	if !acquired {
+		stepDown()
+		return lastRenewal
+	}
+	if IsLeader() {
+		// pick up schedules changed through other replicas
+		err = refreshSchedules()
+	} else {
+		err = becomeLeader()
+	}
+	if err != nil {
+		logrus.Errorf("Error preparing timers. err=%s", err)
+	}
+	return time.Now()
+}
+
+// StopScheduler stops timers and releases leadership so that another replica can take over immediately
+func StopScheduler() {
+	wasLeader := IsLeader()
+	stepDown()
+	if Configuration.HAEnabled && wasLeader {
+		err := Configuration.Db.ReleaseLease(leaseName, Configuration.InstanceID)
+		if err != nil {
+			logrus.Errorf("Error releasing leader lease. err=%s", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+func countTimers() int {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+	return len(scheduledRoutineHashes)
+}
+
+func TestRenewLeadership(t *testing.T) {
+	db := newFakeDB()
+	stale := time.Now().AddDate(-3, 0, 0)
+	db.Insert(ifc.Schedule{
+		Name:          "yearly",
+		Enabled:       true,
+		WorkflowName:  "workflow",
+		CronString:    "0 0 1 1 *",
+		MisfirePolicy: ifc.MisfireSkip,
+		LastFireTime:  &stale,
+	})
+	setupTest(t, db, nil)
+	leaderMutex.Lock()
+	leader = false
+	leaderMutex.Unlock()
+	t.Cleanup(stepDown)
+	ttl := time.Duration(Configuration.LeaseSeconds) * time.Second
+
+	lastRenewal := renewLeadership(ttl, time.Time{})
+	if !IsLeader() || countTimers() != 1 {
+		t.Fatalf("Expected leader with 1 timer after acquiring the lease. leader=%v timers=%d", IsLeader(), countTimers())
+	}
+	if len(db.lastFireTimeUpdates) != 1 {
+		t.Fatalf("Expected missed runs caught up once when the lease is gained, got %d", len(db.lastFireTimeUpdates))
+	}
+
+	// missed runs would be found again if the renewal caught them up
+	db.updateSchedule("yearly", func(schedule *ifc.Schedule) { schedule.LastFireTime = &stale })
+	lastRenewal = renewLeadership(ttl, lastRenewal)
+	if !IsLeader() || len(db.lastFireTimeUpdates) != 1 {
+		t.Fatalf("Expected renewal without catch-up. leader=%v catchUps=%d", IsLeader(), len(db.lastFireTimeUpdates))
+	}
+
+	db.leaseErr = errors.New("connection refused")
+	lastRenewal = renewLeadership(ttl, lastRenewal)
+	if !IsLeader() || countTimers() != 1 {
+		t.Fatalf("Expected leadership kept while the lease has not expired")
+	}
+	renewLeadership(ttl, time.Now().Add(-ttl))
+	if IsLeader() || countTimers() != 0 {
+		t.Fatalf("Expected timers stopped when the lease could not be renewed in time. timers=%d", countTimers())
+	}
+
+	db.leaseErr = nil
+	lastRenewal = renewLeadership(ttl, lastRenewal)
+	if !IsLeader() || len(db.lastFireTimeUpdates) != 2 {
+		t.Fatalf("Expected missed runs caught up again when the lease is gained again. catchUps=%d", len(db.lastFireTimeUpdates))
+	}
+
+	db.leaseHolder = "other"
+	renewLeadership(ttl, lastRenewal)
+	if IsLeader() || countTimers() != 0 {
+		t.Fatalf("Expected timers stopped when the lease is lost. leader=%v timers=%d", IsLeader(), countTimers())
+	}
+	if len(db.lastFireTimeUpdates) != 2 {
+		t.Fatalf("Unexpected catch-up after losing the lease")
+	}
+}
@@ -0,0 +1,308 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// limitPollInterval is how often pending launches are attempted while launch limits are reached
+	limitPollInterval = time.Second
+	// manualLaunchTimeout is how long manual triggers wait for a free launch slot
+	manualLaunchTimeout = 30 * time.Second
+)
+
+// ErrTriggerPending is returned when the trigger was persisted as PENDING execution until launch limits allow it
+var ErrTriggerPending = fmt.Errorf("%w: trigger pending launch", ErrTriggerSkipped)
+
+// launchLimiter enforces LAUNCH_RATE_LIMIT, LAUNCH_MAX_IN_FLIGHT and LAUNCH_WORKFLOW_LIMITS of this replica
+type launchLimiter struct {
+	mutex      sync.Mutex
+	nextLaunch time.Time
+	inFlight   int
+	// launches in progress by workflow name, not recorded as RUNNING executions yet
+	workflowInFlight map[string]int
+	// when pending executions were queued by this replica, by execution id
+	pendingSince map[string]time.Time
+	// pending executions being launched, so that the dispatcher does not pick them again
+	dispatching map[string]bool
+}
+
+var limiter = newLaunchLimiter()
+
+func newLaunchLimiter() *launchLimiter {
+	return &launchLimiter{
+		workflowInFlight: make(map[string]int),
+		pendingSince:     make(map[string]time.Time),
+		dispatching:      make(map[string]bool),
+	}
+}
+
+// launchLimitsEnabled returns true when any of the launch limits is configured
+func launchLimitsEnabled() bool {
+	return Configuration.LaunchRateLimit > 0 || Configuration.LaunchMaxInFlight > 0 || len(Configuration.LaunchWorkflowLimits) > 0
+}
+
+// tryAcquire reserves a launch slot for a workflow if launch limits allow it right now.
+// Returns false as the second value when the rate or in-flight limit is reached and false as the third one
+// when only the cap of the workflow is. The returned function releases the slot once the launch is recorded.
+func (l *launchLimiter) tryAcquire(workflowName string) (func(), bool, bool) {
+	l.mutex.Lock()
+	now := time.Now()
+	if now.Before(l.nextLaunch) || (Configuration.LaunchMaxInFlight > 0 && l.inFlight >= Configuration.LaunchMaxInFlight) {
+		l.mutex.Unlock()
+		return nil, false, true
+	}
+	// the slot is reserved while running workflows are counted, so that the limiter is not locked
+	// during the query and concurrent launches of the workflow count the reserved one
+	previousLaunch := l.nextLaunch
+	if Configuration.LaunchRateLimit > 0 {
+		l.nextLaunch = now.Add(time.Duration(float64(time.Second) / Configuration.LaunchRateLimit))
+	}
+	reservedLaunch := l.nextLaunch
+	l.inFlight++
+	l.workflowInFlight[workflowName]++
+	launchesInFlightGauge.Set(float64(l.inFlight))
+	l.mutex.Unlock()
+
+	released := false
+	release := func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		if released {
+			return
+		}
+		released = true
+		l.inFlight--
+		l.workflowInFlight[workflowName]--
+		launchesInFlightGauge.Set(float64(l.inFlight))
+	}
+
+	if limit, capped := Configuration.LaunchWorkflowLimits[workflowName]; capped {
+		running, err := Configuration.Db.CountExecutions(ifc.ExecutionFilter{WorkflowName: workflowName, Status: "RUNNING"})
+		if err != nil {
+			logrus.Errorf("Error counting running workflows %s. err=%s", workflowName, err)
+		}
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		if err != nil || running+l.workflowInFlight[workflowName] > limit {
+			// roll back the reservation, other launches waited for it only if they did not pass the rate limit meanwhile
+			if l.nextLaunch.Equal(reservedLaunch) {
+				l.nextLaunch = previousLaunch
+			}
+			released = true
+			l.inFlight--
+			l.workflowInFlight[workflowName]--
+			launchesInFlightGauge.Set(float64(l.inFlight))
+			return nil, true, false
+		}
+	}
+	return release, true, true
+}
+
+// acquire waits up to timeout for a launch slot for a workflow
+func (l *launchLimiter) acquire(workflowName string, timeout time.Duration) (func(), error) {
+	started := time.Now()
+	for {
+		release, underLimit, underCap := l.tryAcquire(workflowName)
+		if release != nil {
+			launchWaitHistogram.Observe(time.Since(started).Seconds())
+			return release, nil
+		}
+		if time.Since(started) >= timeout {
+			if !underLimit {
+				return nil, fmt.Errorf("%w: launch limits reached", ErrTriggerSkipped)
+			}
+			if !underCap {
+				return nil, fmt.Errorf("%w: workflow %s reached its limit of running workflows", ErrTriggerSkipped, workflowName)
+			}
+		}
+		time.Sleep(l.retryDelay())
+	}
+}
+
+// retryDelay returns how long to wait before attempting to acquire a launch slot again
+func (l *launchLimiter) retryDelay() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if delay := time.Until(l.nextLaunch); delay > 0 && delay < limitPollInterval {
+		return delay
+	}
+	return limitPollInterval
+}
+
+// tryLaunchSlot reserves a launch slot for a workflow without waiting, nil when launch limits do not allow it now
+func tryLaunchSlot(workflowName string) func() {
+	if !launchLimitsEnabled() {
+		return func() {}
+	}
+	release, _, _ := limiter.tryAcquire(workflowName)
+	if release != nil {
+		launchWaitHistogram.Observe(0)
+	}
+	return release
+}
+
+// acquireLaunchSlot reserves launch of a workflow of the schedule within launch limits. Manual triggers wait
+// for a free slot. Other triggers are persisted as PENDING executions, launched in order by dispatchPendingLaunches,
+// when there is no free slot or earlier triggers are still pending.
+func acquireLaunchSlot(schedule *ifc.Schedule, fireTime time.Time, trigger string) (func(), error) {
+	if !launchLimitsEnabled() {
+		return func() {}, nil
+	}
+	if trigger == TriggerManual {
+		return limiter.acquire(schedule.WorkflowName, manualLaunchTimeout)
+	}
+	pending, err := Configuration.Db.CountExecutions(ifc.ExecutionFilter{Status: "PENDING"})
+	if err != nil {
+		return nil, fmt.Errorf("Error counting pending launches. err=%s", err)
+	}
+	if pending == 0 {
+		if release := tryLaunchSlot(schedule.WorkflowName); release != nil {
+			return release, nil
+		}
+	}
+	return nil, queuePendingLaunch(schedule, fireTime, trigger, pending)
+}
+
+// queuePendingLaunch records the trigger as PENDING execution
+func queuePendingLaunch(schedule *ifc.Schedule, fireTime time.Time, trigger string, pending int) error {
+	execution := ifc.Execution{
+		ID:           uuid.NewString(),
+		ScheduleName: schedule.Name,
+		FireTime:     fireTime,
+		Trigger:      trigger,
+		Status:       "PENDING",
+		WorkflowName: schedule.WorkflowName,
+	}
+	err := Configuration.Db.InsertExecution(execution)
+	if err != nil {
+		return fmt.Errorf("Error saving pending launch. err=%s", err)
+	}
+	limiter.mutex.Lock()
+	limiter.pendingSince[execution.ID] = time.Now()
+	limiter.mutex.Unlock()
+	launchQueueDepthGauge.Set(float64(pending + 1))
+	return fmt.Errorf("%w: schedule %s waits for launch limits. pending=%d", ErrTriggerPending, schedule.Name, pending+1)
+}
+
+// dispatchPendingLaunches launches PENDING executions, the oldest first, while launch limits allow it until stop is closed.
+// Executions of workflows that reached their cap do not hold back the others.
+func dispatchPendingLaunches(stop <-chan struct{}) {
+	logrus.Debugf("Starting to dispatch pending launches")
+	for {
+		dispatchPending()
+		select {
+		case <-stop:
+			logrus.Debugf("Stopping to dispatch pending launches")
+			return
+		case <-time.After(limiter.retryDelay()):
+		}
+	}
+}
+
+func dispatchPending() {
+	pending, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{Status: "PENDING"}, "", 0)
+	if err != nil {
+		logrus.Errorf("Error finding pending launches. err=%s", err)
+		return
+	}
+	launchQueueDepthGauge.Set(float64(len(pending)))
+
+	// executions are ordered from the most recent one
+	for i := len(pending) - 1; i >= 0; i-- {
+		execution := pending[i]
+		limiter.mutex.Lock()
+		dispatching := limiter.dispatching[execution.ID]
+		limiter.mutex.Unlock()
+		if dispatching {
+			continue
+		}
+		schedule, err := Configuration.Db.FindByName(execution.ScheduleName)
+		if err != nil {
+			logrus.Errorf("Couldn't get schedule %s. err=%s", execution.ScheduleName, err)
+			continue
+		}
+		if schedule == nil || !schedule.Enabled {
+			skipWaitingTrigger(execution, "schedule was disabled or removed while waiting for launch limits")
+			continue
+		}
+		release, underLimit, _ := limiter.tryAcquire(schedule.WorkflowName)
+		if !underLimit {
+			return
+		}
+		if release == nil {
+			continue
+		}
+		limiter.mutex.Lock()
+		limiter.dispatching[execution.ID] = true
+		queued, known := limiter.pendingSince[execution.ID]
+		limiter.mutex.Unlock()
+		if !known {
+			// queued before this replica became the leader
+			queued = execution.FireTime
+		}
+		launchWaitHistogram.Observe(time.Since(queued).Seconds())
+		go launchPending(schedule, execution, release)
+	}
+}
+
+// launchPending launches the workflow of a PENDING execution and records the result in it.
+// The trigger is skipped when the schedule does not allow the launch anymore.
+func launchPending(schedule *ifc.Schedule, execution ifc.Execution, release func()) {
+	defer func() {
+		limiter.mutex.Lock()
+		delete(limiter.dispatching, execution.ID)
+		delete(limiter.pendingSince, execution.ID)
+		limiter.mutex.Unlock()
+	}()
+
+	// schedule, its calendars or running workflows may have changed since the trigger was fired
+	runningExecutions, err := checkTrigger(schedule, execution, false)
+	if err != nil {
+		release()
+	}
+	if errors.Is(err, ErrTriggerQueued) {
+		logrus.Infof("Schedule %s: Pending trigger from %s queued", schedule.Name, execution.FireTime)
+		return
+	}
+	if errors.Is(err, ErrTriggerSkipped) {
+		skipWaitingTrigger(execution, err.Error())
+		return
+	}
+	if err != nil {
+		logrus.Errorf("Error checking pending trigger of schedule %s. err=%s", schedule.Name, err)
+		return
+	}
+	logrus.Infof("Schedule %s: Launching pending trigger from %s", schedule.Name, execution.FireTime)
+	_, err = launchExecution(schedule, execution, len(runningExecutions), nil, release)
+	if errors.Is(err, ErrLaunchRetrying) {
+		logrus.Debugf("%s", err)
+	} else if err != nil {
+		logrus.Errorf("Error launching pending trigger of schedule %s. err=%s", schedule.Name, err)
+	}
+}
+
+// skipWaitingTrigger records PENDING or QUEUED execution as SKIPPED for the reason
+func skipWaitingTrigger(execution ifc.Execution, reason string) {
+	logrus.Infof("Schedule %s: Skipping %s trigger from %s, %s", execution.ScheduleName, strings.ToLower(execution.Status),
+		execution.FireTime, reason)
+	limiter.mutex.Lock()
+	delete(limiter.pendingSince, execution.ID)
+	limiter.mutex.Unlock()
+	now := time.Now()
+	execution.Status = "SKIPPED"
+	execution.EndTime = &now
+	execution.Error = reason
+	err := Configuration.Db.UpdateExecution(execution)
+	if err != nil {
+		logrus.Errorf("Error updating execution of schedule %s. err=%s", execution.ScheduleName, err)
+	}
+}
@@ -0,0 +1,313 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+)
+
+// addPendingExecution records a trigger of the schedule waiting for launch limits
+func addPendingExecution(db *fakeDB, scheduleName string, fireTime time.Time) ifc.Execution {
+	execution := ifc.Execution{
+		ID:           uuid.NewString(),
+		ScheduleName: scheduleName,
+		FireTime:     fireTime,
+		Trigger:      TriggerTimer,
+		Status:       "PENDING",
+		WorkflowName: "workflow",
+	}
+	db.InsertExecution(execution)
+	return execution
+}
+
+func TestLaunchPendingChecksSchedule(t *testing.T) {
+	fireTime := time.Now().Add(-time.Minute)
+	toDate := fireTime.Add(-time.Hour)
+	cases := map[string]struct {
+		update func(*ifc.Schedule)
+		// running workflow of the schedule
+		running          bool
+		expectedStatus   string
+		expectedLaunches int
+	}{
+		"allowed":              {func(*ifc.Schedule) {}, false, "RUNNING", 1},
+		"after toDate":         {func(schedule *ifc.Schedule) { schedule.ToDate = &toDate }, false, "SKIPPED", 0},
+		"excluded by calendar": {func(schedule *ifc.Schedule) { schedule.ExcludeCalendars = []string{"always"} }, false, "SKIPPED", 0},
+		"dependency not satisfied": {func(schedule *ifc.Schedule) {
+			schedule.DependsOn = []ifc.Dependency{{ScheduleName: "upstream", Condition: ifc.DependencyCompleted}}
+		}, false, "SKIPPED", 0},
+		"forbid while running": {func(schedule *ifc.Schedule) { schedule.ConcurrencyPolicy = ifc.ConcurrencyForbid }, true, "SKIPPED", 0},
+		"queue while running":  {func(schedule *ifc.Schedule) { schedule.ConcurrencyPolicy = ifc.ConcurrencyQueue }, true, "QUEUED", 0},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			setupTest(t, db, conductor.ServeHTTP)
+			db.InsertCalendar(ifc.Calendar{Name: "always", TimeZone: "UTC", DateRanges: []ifc.DateRange{
+				{From: time.Now().AddDate(-1, 0, 0), To: time.Now().AddDate(1, 0, 0)},
+			}})
+			schedule := newTestSchedule("pending")
+			c.update(&schedule)
+			db.Insert(schedule)
+			if c.running {
+				addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-time.Hour))
+			}
+			execution := addPendingExecution(db, schedule.Name, fireTime)
+
+			released := false
+			launchPending(&schedule, execution, func() { released = true })
+
+			if !released {
+				t.Errorf("Launch slot was not released")
+			}
+			if conductor.launchCount() != c.expectedLaunches {
+				t.Errorf("Unexpected launches %v", conductor.launched)
+			}
+			executions := db.executionsOf(schedule.Name)
+			launched := executions[len(executions)-1]
+			if (c.running && len(executions) != 2) || (!c.running && len(executions) != 1) || launched.ID != execution.ID {
+				t.Fatalf("Expected the pending execution to be updated, got %+v", executions)
+			}
+			if launched.Status != c.expectedStatus {
+				t.Errorf("Unexpected status %s of pending execution. err=%s", launched.Status, launched.Error)
+			}
+			if c.expectedStatus == "SKIPPED" && launched.Error == "" {
+				t.Errorf("Expected reason of skipped pending launch")
+			}
+		})
+	}
+}
+
+func TestTryAcquire(t *testing.T) {
+	cases := map[string]struct {
+		rateLimit      float64
+		maxInFlight    int
+		workflowLimits map[string]int
+		// RUNNING executions of workflow "capped"
+		running int
+		// workflows holding launch slots before the one under test
+		held               []string
+		workflowName       string
+		expectedAcquired   bool
+		expectedUnderLimit bool
+		expectedUnderCap   bool
+	}{
+		"no limits":               {0, 0, nil, 0, []string{"other", "other"}, "workflow", true, true, true},
+		"rate limit":              {1, 0, nil, 0, []string{"other"}, "workflow", false, false, true},
+		"in flight below limit":   {0, 2, nil, 0, []string{"other"}, "workflow", true, true, true},
+		"in flight limit":         {0, 2, nil, 0, []string{"other", "other"}, "workflow", false, false, true},
+		"workflow below cap":      {0, 0, map[string]int{"capped": 2}, 1, nil, "capped", true, true, true},
+		"workflow running at cap": {0, 0, map[string]int{"capped": 1}, 1, nil, "capped", false, true, false},
+		"workflow launching at cap": {0, 0, map[string]int{"capped": 2}, 1, []string{"capped"}, "capped",
+			false, true, false},
+		"other workflow not capped": {0, 0, map[string]int{"capped": 1}, 1, nil, "workflow",
+			true, true, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			setupTest(t, db, nil)
+			Configuration.LaunchRateLimit = c.rateLimit
+			Configuration.LaunchMaxInFlight = c.maxInFlight
+			if c.workflowLimits != nil {
+				Configuration.LaunchWorkflowLimits = c.workflowLimits
+			}
+			for i := 0; i < c.running; i++ {
+				db.InsertExecution(ifc.Execution{ID: uuid.NewString(), WorkflowName: "capped", Status: "RUNNING"})
+			}
+			l := newLaunchLimiter()
+			for _, workflowName := range c.held {
+				if release, _, _ := l.tryAcquire(workflowName); release == nil {
+					t.Fatalf("Could not acquire launch slot for %s", workflowName)
+				}
+			}
+
+			release, underLimit, underCap := l.tryAcquire(c.workflowName)
+			if (release != nil) != c.expectedAcquired || underLimit != c.expectedUnderLimit || underCap != c.expectedUnderCap {
+				t.Fatalf("Unexpected acquired=%v underLimit=%v underCap=%v", release != nil, underLimit, underCap)
+			}
+		})
+	}
+}
+
+func TestTryAcquireRelease(t *testing.T) {
+	setupTest(t, newFakeDB(), nil)
+	Configuration.LaunchMaxInFlight = 1
+	Configuration.LaunchWorkflowLimits = map[string]int{"capped": 1}
+	l := newLaunchLimiter()
+
+	release, _, _ := l.tryAcquire("capped")
+	if release == nil {
+		t.Fatalf("Could not acquire launch slot")
+	}
+	release()
+	// releasing twice must not free a slot held by another launch
+	release()
+	if l.inFlight != 0 || l.workflowInFlight["capped"] != 0 {
+		t.Fatalf("Unexpected in flight launches %d %v", l.inFlight, l.workflowInFlight)
+	}
+	if release, _, _ = l.tryAcquire("capped"); release == nil {
+		t.Fatalf("Launch slot was not released")
+	}
+}
+
+func TestTryAcquireDoesNotLockWhileCounting(t *testing.T) {
+	db := newFakeDB()
+	setupTest(t, db, nil)
+	Configuration.LaunchWorkflowLimits = map[string]int{"capped": 1}
+	l := newLaunchLimiter()
+	db.countBlock = make(chan struct{})
+
+	counted := make(chan func())
+	go func() {
+		release, _, _ := l.tryAcquire("capped")
+		counted <- release
+	}()
+	waitFor(t, func() bool {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		return l.workflowInFlight["capped"] == 1
+	})
+	if release, _, _ := l.tryAcquire("other"); release == nil {
+		t.Fatalf("Could not acquire launch slot of other workflow while running workflows are counted")
+	}
+	close(db.countBlock)
+	if release := <-counted; release == nil {
+		t.Fatalf("Could not acquire launch slot of capped workflow")
+	}
+	if release, underLimit, underCap := l.tryAcquire("capped"); release != nil || !underLimit || underCap {
+		t.Fatalf("Expected the reserved slot counted by the cap, got underLimit=%v underCap=%v", underLimit, underCap)
+	}
+}
+
+func TestTryAcquireRollsBackRateLimit(t *testing.T) {
+	db := newFakeDB()
+	setupTest(t, db, nil)
+	Configuration.LaunchRateLimit = 1
+	Configuration.LaunchWorkflowLimits = map[string]int{"capped": 1}
+	db.InsertExecution(ifc.Execution{ID: uuid.NewString(), WorkflowName: "capped", Status: "RUNNING"})
+	l := newLaunchLimiter()
+
+	if release, _, underCap := l.tryAcquire("capped"); release != nil || underCap {
+		t.Fatalf("Expected capped workflow not acquired")
+	}
+	if release, _, _ := l.tryAcquire("other"); release == nil {
+		t.Fatalf("Rejected launch of capped workflow held back the rate limit")
+	}
+	if l.inFlight != 1 || l.workflowInFlight["capped"] != 0 {
+		t.Fatalf("Unexpected in flight launches %d %v", l.inFlight, l.workflowInFlight)
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	cases := map[string]struct {
+		maxInFlight    int
+		workflowLimits map[string]int
+		// release the held slot while acquire waits
+		releaseHeld bool
+		expectedErr string
+	}{
+		"launch limits reached": {1, nil, false, "launch limits reached"},
+		"workflow capped":       {0, map[string]int{"workflow": 1}, false, "workflow workflow reached its limit of running workflows"},
+		"slot released":         {1, nil, true, ""},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			setupTest(t, newFakeDB(), nil)
+			Configuration.LaunchMaxInFlight = c.maxInFlight
+			if c.workflowLimits != nil {
+				Configuration.LaunchWorkflowLimits = c.workflowLimits
+			}
+			l := newLaunchLimiter()
+			held, _, _ := l.tryAcquire("workflow")
+			timeout := 10 * time.Millisecond
+			if c.releaseHeld {
+				timeout = 5 * time.Second
+				time.AfterFunc(50*time.Millisecond, held)
+			}
+
+			release, err := l.acquire("workflow", timeout)
+			if c.expectedErr == "" {
+				if err != nil || release == nil {
+					t.Fatalf("Unexpected error %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrTriggerSkipped) || !strings.Contains(err.Error(), c.expectedErr) || release != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+// waitForDispatched waits until pending launches started by dispatchPending finish
+func waitForDispatched(t *testing.T) {
+	waitFor(t, func() bool {
+		limiter.mutex.Lock()
+		defer limiter.mutex.Unlock()
+		return len(limiter.dispatching) == 0
+	})
+}
+
+func TestDispatchPendingOldestFirst(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	Configuration.LaunchMaxInFlight = 1
+	now := time.Now()
+	for name, minutesAgo := range map[string]int{"second": 2, "first": 3, "third": 1} {
+		db.Insert(newTestSchedule(name))
+		addPendingExecution(db, name, now.Add(-time.Duration(minutesAgo)*time.Minute))
+	}
+
+	expectedOrder := []string{"first", "second", "third"}
+	for i, name := range expectedOrder {
+		dispatchPending()
+		waitForDispatched(t)
+		if conductor.launchCount() != i+1 {
+			t.Fatalf("Expected one pending launch per dispatch within in flight limit, got %v", conductor.launched)
+		}
+		execution := db.executionsOf(name)[0]
+		if execution.Status != "RUNNING" || execution.WorkflowID != conductor.launched[i] {
+			t.Fatalf("Expected pending launch of %s dispatched %d., got %+v", name, i+1, execution)
+		}
+	}
+}
+
+func TestDispatchPendingSkipsCappedWorkflows(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	Configuration.LaunchWorkflowLimits = map[string]int{"capped": 1}
+	now := time.Now()
+	capped := newTestSchedule("capped")
+	capped.WorkflowName = "capped"
+	db.Insert(capped)
+	addRunningExecution(db, conductor, "other", "wf-capped", now.Add(-time.Hour))
+	db.updateExecution("wf-capped", func(execution *ifc.Execution) { execution.WorkflowName = "capped" })
+	addPendingExecution(db, "capped", now.Add(-2*time.Minute))
+	db.Insert(newTestSchedule("free"))
+	addPendingExecution(db, "free", now.Add(-time.Minute))
+	disabled := newTestSchedule("disabled")
+	disabled.Enabled = false
+	db.Insert(disabled)
+	addPendingExecution(db, "disabled", now.Add(-3*time.Minute))
+
+	dispatchPending()
+	waitForDispatched(t)
+
+	statuses := map[string]string{}
+	for _, name := range []string{"capped", "free", "disabled"} {
+		statuses[name] = db.executionsOf(name)[0].Status
+	}
+	expected := map[string]string{"capped": "PENDING", "free": "RUNNING", "disabled": "SKIPPED"}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Fatalf("Unexpected pending launches %v", statuses)
+	}
+}
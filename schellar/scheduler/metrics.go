@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	leaderGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "schellar_leader",
+		Help: "1 if this replica runs schedule timers, 0 if it is a standby",
+	})
+	leaderTransitionsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "schellar_leader_transitions_total",
+		Help: "Number of times this replica gained or lost leadership",
+	})
+	longRunningWorkflowsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "schellar_long_running_workflows",
+		Help: "Number of workflows launched by the schedule running longer than its checkWarningSeconds",
+	}, []string{"schedule"})
+	longRunningWorkflowWarningsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_long_running_workflow_warnings_total",
+		Help: "Number of workflows launched by the schedule that exceeded its checkWarningSeconds",
+	}, []string{"schedule"})
+	overrunTerminationsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_overrun_workflow_terminations_total",
+		Help: "Number of workflows launched by the schedule terminated for running longer than its maxRunDuration",
+	}, []string{"schedule"})
+	launchRetriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_launch_retries_total",
+		Help: "Number of failed workflow launches of the schedule attempted again according to its retry policy",
+	}, []string{"schedule"})
+	launchFailuresCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_launch_failures_total",
+		Help: "Number of triggers of the schedule whose workflow could not be launched, including retries",
+	}, []string{"schedule"})
+	failureRecoveriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_failure_recoveries_total",
+		Help: "Number of failed workflows of the schedule retried, restarted or relaunched according to its onFailure policy",
+	}, []string{"schedule", "action"})
+	concurrencyActionsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_concurrency_actions_total",
+		Help: "Number of triggers of the schedule skipped, queued or replacing a running workflow according to its concurrency policy",
+	}, []string{"schedule", "action"})
+	dependencySkipsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_dependency_skips_total",
+		Help: "Number of triggers of the schedule skipped because its dependencies were not satisfied",
+	}, []string{"schedule"})
+	workflowEventsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_workflow_events_total",
+		Help: "Number of received workflow events by result (PROCESSED, IGNORED, ERROR, REJECTED)",
+	}, []string{"result"})
+	calendarSkipsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_calendar_skips_total",
+		Help: "Number of triggers of the schedule skipped because its calendars forbid launching workflows at the time",
+	}, []string{"schedule"})
+	contextUpdatesRejectedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schellar_context_updates_rejected_total",
+		Help: "Number of workflow outputs not stored to workflow context of the schedule because it would exceed maxContextBytes",
+	}, []string{"schedule"})
+	launchQueueDepthGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "schellar_launch_queue_depth",
+		Help: "Number of triggers persisted as PENDING executions waiting for launch limits",
+	})
+	launchWaitHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "schellar_launch_wait_seconds",
+		Help:    "Time triggers waited for launch limits before their workflow was launched",
+		Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+	})
+	launchesInFlightGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "schellar_launches_in_flight",
+		Help: "Number of workflow launches in progress counted against LAUNCH_MAX_IN_FLIGHT",
+	})
+)
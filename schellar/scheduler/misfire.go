@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/sirupsen/logrus"
+)
+
+// maxMissedFireTimes limits how far back missed fire times are computed for frequent cron strings
+const maxMissedFireTimes = 1000
+
+// catchUpMissedRuns applies misfire policy of enabled schedules for timer triggers
+// missed while no replica was running the timers. Triggers missed while a schedule was disabled or paused
+// are not caught up. Missed runs are launched in background.
+func catchUpMissedRuns() error {
+	schedules, err := Configuration.Db.FindAllByEnabled(true)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, schedule := range schedules {
+		since := schedule.MisfireSince()
+		if since == nil {
+			continue
+		}
+		missed, err := schedule.FireTimesBetween(*since, now, maxMissedFireTimes)
+		if err != nil {
+			logrus.Errorf("Cannot compute missed fire times of schedule %s. err=%s", schedule.Name, err)
+			continue
+		}
+		if len(missed) == 0 {
+			continue
+		}
+
+		var fireTimes []time.Time
+		switch schedule.MisfirePolicy {
+		case ifc.MisfireFireOnce:
+			fireTimes = missed[len(missed)-1:]
+		case ifc.MisfireFireAll:
+			fireTimes = missed
+			if len(fireTimes) > schedule.MisfireMaxCount {
+				fireTimes = fireTimes[len(fireTimes)-schedule.MisfireMaxCount:]
+			}
+		}
+		logrus.Infof("Schedule %s: Missed %d timer triggers since %s. misfirePolicy=%s. firing=%d",
+			schedule.Name, len(missed), since, schedule.MisfirePolicy, len(fireTimes))
+
+		err = Configuration.Db.UpdateLastFireTime(schedule.Name, missed[len(missed)-1])
+		if err != nil {
+			logrus.Errorf("Error saving last fire time of schedule %s. err=%s", schedule.Name, err)
+			continue
+		}
+		if len(fireTimes) > 0 {
+			go fireMissed(schedule.Name, fireTimes)
+		}
+	}
+	return nil
+}
+
+func fireMissed(scheduleName string, fireTimes []time.Time) {
+	for _, fireTime := range fireTimes {
+		_, err := FireSchedule(scheduleName, fireTime, TriggerMisfire, nil, false)
+		if errors.Is(err, ErrTriggerSkipped) {
+			logrus.Debugf("%s", err)
+		} else if err != nil {
+			logrus.Errorf("Error processing missed trigger of schedule %s at %s. err=%s", scheduleName, fireTime, err)
+		}
+	}
+}
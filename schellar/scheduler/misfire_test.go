@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+func TestCatchUpMissedRunsSinceEnabled(t *testing.T) {
+	db := newFakeDB()
+	lastFireTime := time.Now().AddDate(-2, 0, 0)
+	enabledAt := time.Now().Add(-time.Minute)
+	for _, schedule := range []ifc.Schedule{
+		{Name: "running", LastFireTime: &lastFireTime},
+		{Name: "reenabled", LastFireTime: &lastFireTime, EnabledAt: &enabledAt},
+	} {
+		schedule.Enabled = true
+		schedule.WorkflowName = "workflow"
+		schedule.CronString = "0 0 1 1 *"
+		schedule.MisfirePolicy = ifc.MisfireSkip
+		db.Insert(schedule)
+	}
+	setupTest(t, db, nil)
+
+	err := catchUpMissedRuns()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(db.lastFireTimeUpdates) != 1 || db.lastFireTimeUpdates[0] != "running" {
+		t.Fatalf("Expected only triggers missed while enabled to be caught up, got %v", db.lastFireTimeUpdates)
+	}
+	if !db.schedule("reenabled").LastFireTime.Equal(lastFireTime) {
+		t.Errorf("Unexpected last fire time of re-enabled schedule %v", db.schedule("reenabled").LastFireTime)
+	}
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/sirupsen/logrus"
+)
+
+// refreshNextFireTimes stores the upcoming fire time of every schedule, so that schedules can be sorted by it.
+// Only changed fire times are written. Called periodically by the leader, as fire times also change when calendars
+// do or time passes.
+func refreshNextFireTimes() error {
+	schedules, err := Configuration.Db.FindAll()
+	if err != nil {
+		return err
+	}
+	allCalendars, err := Configuration.Db.FindAllCalendars()
+	if err != nil {
+		return err
+	}
+	calendars := make(map[string]ifc.Calendar)
+	for _, calendar := range allCalendars {
+		calendars[calendar.Name] = calendar
+	}
+	now := time.Now()
+	for i := range schedules {
+		storeNextFireTime(&schedules[i], now, calendars)
+	}
+	return nil
+}
+
+// RefreshNextFireTime stores the upcoming fire time of the schedule after it was changed or its timer fired
+func RefreshNextFireTime(scheduleName string) {
+	schedule, err := Configuration.Db.FindByName(scheduleName)
+	if err != nil || schedule == nil {
+		logrus.Errorf("Couldn't get schedule %s. err=%v", scheduleName, err)
+		return
+	}
+	calendars, err := LoadCalendars(schedule)
+	if err != nil {
+		logrus.Errorf("Error loading calendars of schedule %s. err=%s", scheduleName, err)
+		return
+	}
+	storeNextFireTime(schedule, time.Now(), calendars)
+}
+
+func storeNextFireTime(schedule *ifc.Schedule, now time.Time, calendars map[string]ifc.Calendar) {
+	var nextFireTime *time.Time
+	fireTimes, err := schedule.NextAllowedFireTimes(now, 1, calendars)
+	if err != nil {
+		logrus.Warnf("Couldn't compute next fire time of schedule %s. err=%s", schedule.Name, err)
+	} else if len(fireTimes) > 0 {
+		nextFireTime = &fireTimes[0]
+	}
+	if sameTime(schedule.NextFireTime, nextFireTime) {
+		return
+	}
+	err = Configuration.Db.UpdateNextFireTime(schedule.Name, nextFireTime)
+	if err != nil {
+		logrus.Errorf("Error saving next fire time of schedule %s. err=%s", schedule.Name, err)
+	}
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// refreshSchedules prepares timers and refreshes next fire times of all schedules, periodically on the leader
+func refreshSchedules() error {
+	err := PrepareTimers()
+	if err != nil {
+		return err
+	}
+	if !IsLeader() {
+		return nil
+	}
+	return refreshNextFireTimes()
+}
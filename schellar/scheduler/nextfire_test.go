@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrepareTimersRefreshesOnlyChangedSchedules(t *testing.T) {
+	db := newFakeDB()
+	setupTest(t, db, nil)
+	t.Cleanup(func() {
+		leaderMutex.Lock()
+		leader = false
+		leaderMutex.Unlock()
+		PrepareTimers()
+	})
+	for _, name := range []string{"changed", "other"} {
+		db.Insert(newTestSchedule(name))
+	}
+
+	err := PrepareTimers()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	RefreshNextFireTime("changed")
+	if db.schedule("changed").NextFireTime == nil || db.schedule("other").NextFireTime != nil {
+		t.Fatalf("Expected next fire time refreshed only for the changed schedule")
+	}
+
+	err = refreshSchedules()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	expected := time.Date(time.Now().Year()+1, 1, 1, 0, 0, 0, 0, time.Local)
+	if next := db.schedule("other").NextFireTime; next == nil || !next.Equal(expected) {
+		t.Errorf("Expected next fire times of all schedules refreshed periodically, got %v", next)
+	}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notification events
+const (
+	EventLongRunningWorkflow = "LONG_RUNNING_WORKFLOW"
+	EventOverrunTerminated   = "OVERRUN_TERMINATED"
+	EventScheduleResumed     = "SCHEDULE_RESUMED"
+)
+
+// Notification is posted as JSON to NOTIFICATION_URL when a schedule needs attention
+type Notification struct {
+	Event        string    `json:"event"`
+	ScheduleName string    `json:"scheduleName"`
+	WorkflowID   string    `json:"workflowId,omitempty"`
+	Message      string    `json:"message"`
+	Time         time.Time `json:"time"`
+}
+
+// notify sends the notification in background if NOTIFICATION_URL is configured
+func notify(notification Notification) {
+	if Configuration.NotificationURL == "" {
+		return
+	}
+	notification.Time = time.Now()
+	go func() {
+		data, _ := json.Marshal(notification)
+		client := &http.Client{
+			Timeout: time.Second * 10,
+		}
+		response, err := client.Post(Configuration.NotificationURL, "application/json", bytes.NewBuffer(data))
+		if err != nil {
+			logrus.Errorf("Sending %s notification failed. err=%s", notification.Event, err)
+			return
+		}
+		defer response.Body.Close()
+		if response.StatusCode >= 300 {
+			logrus.Warnf("Sending %s notification failed. status=%d", notification.Event, response.StatusCode)
+		}
+	}()
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/sirupsen/logrus"
+)
+
+// maxContextVersions is how many replaced workflow contexts are kept for every schedule
+const maxContextVersions = 20
+
+// SaveContextVersion stores current workflow context of the schedule before it is replaced by source
+func SaveContextVersion(schedule ifc.Schedule, source string, workflowID string) error {
+	context := schedule.WorkflowContext
+	if context == nil {
+		context = make(map[string]interface{})
+	}
+	return Configuration.Db.InsertContextVersion(ifc.ContextVersion{
+		ScheduleName:    schedule.Name,
+		WorkflowContext: context,
+		Source:          source,
+		WorkflowID:      workflowID,
+		Created:         time.Now(),
+	}, maxContextVersions)
+}
+
+// mergeWorkflowOutput updates workflow context of the schedule with output of its finished workflow
+// according to its outputMapping. The replaced context is versioned so that it can be restored.
+func mergeWorkflowOutput(schedule *ifc.Schedule, workflow map[string]interface{}) {
+	output, _ := workflow["output"].(map[string]interface{})
+	if len(output) == 0 {
+		return
+	}
+	workflowID := GetStringValue(workflow, "workflowId", "")
+	context, err := schedule.MergeOutput(output)
+	if err != nil {
+		logrus.Warnf("Schedule %s: Not updating workflow context from output of workflow %s. err=%s",
+			schedule.Name, workflowID, err)
+		contextUpdatesRejectedCounter.WithLabelValues(schedule.Name).Inc()
+		return
+	}
+	if reflect.DeepEqual(context, schedule.WorkflowContext) {
+		return
+	}
+	logrus.Debugf("Adding last workflow output to schedule context. output=%s, workflowContext=%s",
+		output, schedule.WorkflowContext)
+	err = SaveContextVersion(*schedule, ifc.ContextSourceOutput, workflowID)
+	if err != nil {
+		logrus.Errorf("Error saving workflow context version of schedule %s. err=%s", schedule.Name, err)
+	}
+	schedule.WorkflowContext = context
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/sirupsen/logrus"
+)
+
+// resumeExpiredPauses enables paused schedules whose pausedUntil has passed and returns them
+func resumeExpiredPauses() ([]ifc.Schedule, error) {
+	disabledSchedules, err := Configuration.Db.FindAllByEnabled(false)
+	if err != nil {
+		return nil, err
+	}
+	resumed := make([]ifc.Schedule, 0)
+	now := time.Now()
+	for _, schedule := range disabledSchedules {
+		if !schedule.PauseExpired(now) {
+			continue
+		}
+		// only the pause is updated, so that changes of the schedule made meanwhile are kept
+		ok, err := Configuration.Db.ResumePause(schedule.Name, *schedule.PausedUntil, now)
+		if err != nil {
+			logrus.Errorf("Error resuming schedule %s. err=%s", schedule.Name, err)
+			continue
+		}
+		if !ok {
+			logrus.Debugf("Schedule %s: Pause changed before it was resumed", schedule.Name)
+			continue
+		}
+		logrus.WithFields(logrus.Fields{
+			"event":       EventScheduleResumed,
+			"schedule":    schedule.Name,
+			"pausedBy":    schedule.PausedBy,
+			"pauseReason": schedule.PauseReason,
+			"pausedUntil": schedule.PausedUntil,
+		}).Info("Paused schedule resumed automatically")
+		pausedBy := schedule.PausedBy
+		schedule.Resume(now)
+		notify(Notification{
+			Event:        EventScheduleResumed,
+			ScheduleName: schedule.Name,
+			Message:      fmt.Sprintf("Schedule paused by %s resumed at the end of its pause", pausedBy),
+		})
+		resumed = append(resumed, schedule)
+	}
+	return resumed, nil
+}
+
+// refreshTimers periodically prepares timers until stop is closed, so that expired pauses are resumed and next fire
+// times follow calendar changes.
+// Leader in HA mode refreshes timers on every renewal of its lease instead.
+func refreshTimers(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Duration(Configuration.CheckIntervalSeconds) * time.Second):
+		}
+		err := refreshSchedules()
+		if err != nil {
+			logrus.Errorf("Error preparing timers. err=%s", err)
+		}
+	}
+}
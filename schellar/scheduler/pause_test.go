@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+// staleDB returns disabled schedules read before they were changed by somebody else
+type staleDB struct {
+	*fakeDB
+	disabled []ifc.Schedule
+}
+
+func (db staleDB) FindAllByEnabled(enabled bool) ([]ifc.Schedule, error) {
+	return db.disabled, nil
+}
+
+func TestResumeExpiredPausesKeepsChanges(t *testing.T) {
+	db := newFakeDB()
+	setupTest(t, db, nil)
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+	disabled := make([]ifc.Schedule, 0)
+	for _, name := range []string{"updated", "paused-again"} {
+		schedule := newTestSchedule(name)
+		schedule.Pause("alice", "incident", &expired, now.Add(-time.Hour))
+		db.Insert(schedule)
+		disabled = append(disabled, schedule)
+	}
+	Configuration.Db = staleDB{db, disabled}
+	later := now.Add(time.Hour)
+	db.updateSchedule("updated", func(schedule *ifc.Schedule) {
+		schedule.WorkflowContext = map[string]interface{}{"updated": true}
+	})
+	db.updateSchedule("paused-again", func(schedule *ifc.Schedule) { schedule.PausedUntil = &later })
+
+	resumed, err := resumeExpiredPauses()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(resumed) != 1 || resumed[0].Name != "updated" {
+		t.Fatalf("Unexpected resumed schedules %v", resumed)
+	}
+	updated := db.schedule("updated")
+	if !updated.Enabled || updated.IsPaused() || updated.PausedBy != "" || updated.EnabledAt == nil ||
+		updated.WorkflowContext["updated"] != true {
+		t.Errorf("Expected the pause cleared keeping other changes, got %+v", updated)
+	}
+	pausedAgain := db.schedule("paused-again")
+	if pausedAgain.Enabled || !pausedAgain.PausedUntil.Equal(later) {
+		t.Errorf("Expected the schedule paused again kept paused, got %+v", pausedAgain)
+	}
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// recoverFailedWorkflow applies onFailure policy of the schedule to the execution of a finished workflow.
+// Returns true when the failed workflow was retried, restarted or relaunched and the schedule is running again.
+func recoverFailedWorkflow(schedule ifc.Schedule, execution ifc.Execution, workflow map[string]interface{}) bool {
+	if execution.Status != "FAILED" && execution.Status != "TIMED_OUT" {
+		return false
+	}
+	if schedule.OnFailure == "" || schedule.OnFailure == ifc.FailureNone {
+		return false
+	}
+	if execution.Recoveries >= schedule.OnFailureMaxCount {
+		logrus.Infof("Schedule %s: Workflow %s ended %s, onFailure limit reached. onFailureMaxCount=%d",
+			schedule.Name, execution.WorkflowID, execution.Status, schedule.OnFailureMaxCount)
+		return false
+	}
+
+	logrus.Infof("Schedule %s: Workflow %s ended %s, applying onFailure %s. recovery=%d/%d",
+		schedule.Name, execution.WorkflowID, execution.Status, schedule.OnFailure,
+		execution.Recoveries+1, schedule.OnFailureMaxCount)
+	var err error
+	switch schedule.OnFailure {
+	case ifc.FailureRetry:
+		err = rerunExecution(execution, "retry")
+	case ifc.FailureRestart:
+		err = rerunExecution(execution, "restart")
+	case ifc.FailureRelaunch:
+		err = relaunchExecution(schedule, execution, workflow)
+	default:
+		err = fmt.Errorf("unknown onFailure %s", schedule.OnFailure)
+	}
+	if err != nil {
+		logrus.Errorf("Error applying onFailure %s to workflow %s of schedule %s. err=%s",
+			schedule.OnFailure, execution.WorkflowID, schedule.Name, err)
+		return false
+	}
+	failureRecoveriesCounter.WithLabelValues(schedule.Name, schedule.OnFailure).Inc()
+	return true
+}
+
+// rerunExecution retries or restarts the same Conductor workflow instance, so its execution is running again
+func rerunExecution(execution ifc.Execution, action string) error {
+	err := rerunWorkflow(execution.WorkflowID, action)
+	if err != nil {
+		return err
+	}
+	execution.Status = "RUNNING"
+	execution.EndTime = nil
+	execution.Error = ""
+	execution.Recoveries++
+	err = Configuration.Db.UpdateExecution(execution)
+	if err != nil {
+		return fmt.Errorf("Error updating execution of workflow %s. err=%s", execution.WorkflowID, err)
+	}
+	return nil
+}
+
+// relaunchExecution launches a fresh workflow with the input of the failed one, recorded as a new execution of the same fire time
+func relaunchExecution(schedule ifc.Schedule, failed ifc.Execution, workflow map[string]interface{}) error {
+	input, _ := workflow["input"].(map[string]interface{})
+	workflowID, launchErr := launchWorkflow(&schedule, failed.FireTime, TriggerRelaunch, input)
+
+	now := time.Now()
+	execution := ifc.Execution{
+		ID:           uuid.NewString(),
+		ScheduleName: schedule.Name,
+		FireTime:     failed.FireTime,
+		Trigger:      TriggerRelaunch,
+		WorkflowID:   workflowID,
+		Status:       "RUNNING",
+		StartTime:    &now,
+		Attempts:     1,
+		Recoveries:   failed.Recoveries + 1,
+		WorkflowName: schedule.WorkflowName,
+	}
+	if launchErr != nil {
+		execution.Status = "FAILED"
+		execution.EndTime = &now
+		execution.Error = launchErr.Error()
+	}
+	err := Configuration.Db.InsertExecution(execution)
+	if err != nil {
+		logrus.Errorf("Error saving execution of schedule %s. err=%s", schedule.Name, err)
+	}
+	return launchErr
+}
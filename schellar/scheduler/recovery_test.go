@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+func TestRefreshScheduleRecoversFailedWorkflow(t *testing.T) {
+	cases := map[string]struct {
+		onFailure      string
+		recoveries     int
+		expectedReruns []string
+		expectedStatus string
+		// expected executions by workflowId/trigger
+		expectedExecutions map[string]string
+	}{
+		"none": {ifc.FailureNone, 0, nil, "FAILED",
+			map[string]string{"wf-old/TIMER": "FAILED"}},
+		"retry": {ifc.FailureRetry, 0, []string{"retry wf-old"}, "RUNNING",
+			map[string]string{"wf-old/TIMER": "RUNNING"}},
+		"restart": {ifc.FailureRestart, 0, []string{"restart wf-old"}, "RUNNING",
+			map[string]string{"wf-old/TIMER": "RUNNING"}},
+		"relaunch": {ifc.FailureRelaunch, 0, nil, "RUNNING",
+			map[string]string{"wf-old/TIMER": "FAILED", "wf-1/RELAUNCH": "RUNNING"}},
+		"limit reached": {ifc.FailureRetry, 2, nil, "FAILED",
+			map[string]string{"wf-old/TIMER": "FAILED"}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			setupTest(t, db, conductor.ServeHTTP)
+			schedule := newTestSchedule("recovery")
+			schedule.OnFailure = c.onFailure
+			schedule.OnFailureMaxCount = 2
+			db.Insert(schedule)
+			// execution persisted as RUNNING before a restart, its workflow failed meanwhile
+			addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-time.Hour))
+			db.updateExecution("wf-old", func(execution *ifc.Execution) { execution.Recoveries = c.recoveries })
+			conductor.finish("wf-old", "FAILED", nil)
+
+			refreshSchedule(db.schedule(schedule.Name))
+
+			if len(conductor.reruns) != len(c.expectedReruns) ||
+				(len(c.expectedReruns) > 0 && conductor.reruns[0] != c.expectedReruns[0]) {
+				t.Errorf("Unexpected reruns %v", conductor.reruns)
+			}
+			statuses := executionStatuses(db, schedule.Name)
+			if len(statuses) != len(c.expectedExecutions) {
+				t.Fatalf("Unexpected executions %v", statuses)
+			}
+			for key, status := range c.expectedExecutions {
+				if statuses[key] != status {
+					t.Errorf("Unexpected executions %v", statuses)
+				}
+			}
+			if db.schedule(schedule.Name).Status != c.expectedStatus {
+				t.Errorf("Unexpected schedule status %s", db.schedule(schedule.Name).Status)
+			}
+		})
+	}
+}
+
+func TestRefreshScheduleRecordsRunningExecutionsAfterRestart(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	schedule := newTestSchedule("restarted")
+	db.Insert(schedule)
+	addRunningExecution(db, conductor, schedule.Name, "wf-done", time.Now().Add(-time.Hour))
+	addRunningExecution(db, conductor, schedule.Name, "wf-running", time.Now().Add(-time.Minute))
+	conductor.finish("wf-done", "COMPLETED", map[string]interface{}{"offset": 10})
+
+	refreshSchedule(db.schedule(schedule.Name))
+
+	statuses := executionStatuses(db, schedule.Name)
+	if statuses["wf-done/TIMER"] != "COMPLETED" || statuses["wf-running/TIMER"] != "RUNNING" {
+		t.Fatalf("Unexpected executions %v", statuses)
+	}
+	if db.schedule(schedule.Name).Status != "RUNNING" {
+		t.Errorf("Schedule with a running workflow has to stay RUNNING, got %s", db.schedule(schedule.Name).Status)
+	}
+
+	conductor.finish("wf-running", "COMPLETED", map[string]interface{}{"offset": 20})
+	refreshSchedule(db.schedule(schedule.Name))
+	if db.schedule(schedule.Name).Status != "COMPLETED" {
+		t.Errorf("Unexpected schedule status %s", db.schedule(schedule.Name).Status)
+	}
+	lastExecution, _ := db.schedule(schedule.Name).WorkflowContext["lastExecution"].(map[string]interface{})
+	if lastExecution["offset"] != float64(20) {
+		t.Errorf("Expected context of the latest workflow, got %v", db.schedule(schedule.Name).WorkflowContext)
+	}
+}
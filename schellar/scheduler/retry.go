@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// conductorStatusError is returned when Conductor responds with unexpected HTTP status
+type conductorStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *conductorStatusError) Error() string {
+	return fmt.Sprintf("%s. status=%d", err.Message, err.StatusCode)
+}
+
+// isRetryableLaunchError returns true for failures that may disappear when Conductor becomes available again,
+// requests rejected by Conductor (e.g. unknown workflow) are not retried
+func isRetryableLaunchError(err error) bool {
+	if errors.Is(err, ErrInvalidTemplate) {
+		return false
+	}
+	var statusErr *conductorStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+	return true
+}
+
+// ErrLaunchRetrying is returned when the failed launch is recorded as RETRYING execution and attempted again in background
+var ErrLaunchRetrying = fmt.Errorf("%w: launch retrying", ErrTriggerSkipped)
+
+// errRetryAbandoned is returned when the retried launch must not be recorded anymore, because this replica
+// lost leadership or the execution was finished by somebody else meanwhile
+var errRetryAbandoned = errors.New("launch retry abandoned")
+
+var (
+	// activeRetries holds ids of RETRYING executions whose launch is retried by this replica
+	activeRetries      = make(map[string]bool)
+	activeRetriesMutex sync.Mutex
+)
+
+// launchExecution launches workflow of the execution, records the result in it and updates status of the schedule.
+// Failed launches are recorded as RETRYING execution and attempted again in background according to retry policy
+// of the schedule, so that timers are not blocked by retry delays, ErrLaunchRetrying is returned then.
+// Manual triggers are attempted only once, their caller gets the error right away.
+// release frees the launch slot once the launch is recorded, running is number of running workflows of the schedule.
+func launchExecution(schedule *ifc.Schedule, execution ifc.Execution, running int, inputOverride map[string]interface{}, release func()) (string, error) {
+	maxAttempts := schedule.RetryPolicy.Attempts()
+	if execution.Trigger == TriggerManual {
+		maxAttempts = 1
+	}
+	workflowID, err := launchWorkflow(schedule, execution.FireTime, execution.Trigger, inputOverride)
+	if err == nil || maxAttempts <= 1 || !isRetryableLaunchError(err) {
+		defer release()
+		return workflowID, finishLaunch(schedule, execution, running, workflowID, 1, err)
+	}
+
+	execution = recordRetry(schedule, execution, 1, err)
+	if running == 0 {
+		statusErr := Configuration.Db.UpdateStatus(schedule.Name, "RETRYING")
+		if statusErr != nil {
+			logrus.Errorf("Error saving Schedule status err=%s", statusErr)
+		}
+	}
+	go func() {
+		defer release()
+		defer func() {
+			activeRetriesMutex.Lock()
+			delete(activeRetries, execution.ID)
+			activeRetriesMutex.Unlock()
+		}()
+		workflowID, attempts, err := retryLaunch(schedule, execution, maxAttempts, inputOverride, err)
+		if errors.Is(err, errRetryAbandoned) {
+			logrus.Infof("Schedule %s: Launch retries of trigger from %s abandoned. err=%s", schedule.Name, execution.FireTime, err)
+			return
+		}
+		// other workflows of the schedule may have finished while the launch was retried
+		if executions, findErr := findRunningExecutions(schedule.Name); findErr == nil {
+			running = 0
+			for _, other := range executions {
+				if other.ID != execution.ID {
+					running++
+				}
+			}
+		} else {
+			logrus.Errorf("Error finding running executions of schedule %s. err=%s", schedule.Name, findErr)
+		}
+		err = finishLaunch(schedule, execution, running, workflowID, attempts, err)
+		if err != nil {
+			logrus.Errorf("Error launching workflow of schedule %s. err=%s", schedule.Name, err)
+		}
+	}()
+	return "", fmt.Errorf("%w: schedule %s launch attempt 1/%d failed. err=%s", ErrLaunchRetrying, schedule.Name, maxAttempts, err)
+}
+
+// retryLaunch attempts the failed launch of the RETRYING execution again until it succeeds, fails with an error
+// that is not retried or maxAttempts are reached. Returns workflowId, number of attempts and error of the last attempt.
+// errRetryAbandoned is returned when this replica lost leadership or the execution is not RETRYING anymore,
+// the new leader records such executions then.
+func retryLaunch(schedule *ifc.Schedule, execution ifc.Execution, maxAttempts int, inputOverride map[string]interface{}, err error) (string, int, error) {
+	for attempt := 1; ; {
+		if !IsLeader() {
+			return "", attempt, fmt.Errorf("%w. not retried, this replica is not the leader anymore. err=%s", errRetryAbandoned, err)
+		}
+		delay := schedule.RetryPolicy.Delay(attempt)
+		logrus.Warnf("Schedule %s: Launch attempt %d/%d failed, retrying in %s. err=%s",
+			schedule.Name, attempt, maxAttempts, delay, err)
+		launchRetriesCounter.WithLabelValues(schedule.Name).Inc()
+		time.Sleep(delay)
+
+		// leadership may have moved while sleeping and the new leader fails retries it does not run
+		if !IsLeader() {
+			return "", attempt, fmt.Errorf("%w. not retried, this replica is not the leader anymore", errRetryAbandoned)
+		}
+		retrying, findErr := isStillRetrying(execution)
+		if findErr != nil {
+			return "", attempt, fmt.Errorf("%w. err=%s", errRetryAbandoned, findErr)
+		}
+		if !retrying {
+			return "", attempt, fmt.Errorf("%w. execution is not RETRYING anymore", errRetryAbandoned)
+		}
+		attempt++
+		var workflowID string
+		workflowID, err = launchWorkflow(schedule, execution.FireTime, execution.Trigger, inputOverride)
+		if err == nil {
+			logrus.Infof("Schedule %s: Workflow launched after %d attempts", schedule.Name, attempt)
+			return workflowID, attempt, nil
+		}
+		if attempt >= maxAttempts || !isRetryableLaunchError(err) {
+			return "", attempt, err
+		}
+		execution = recordRetry(schedule, execution, attempt, err)
+	}
+}
+
+// recordRetry persists the execution as RETRYING after failed launch attempt, so that it is visible and
+// counted by concurrency policy of the schedule until the launch is retried. Returns the persisted execution.
+func recordRetry(schedule *ifc.Schedule, execution ifc.Execution, attempts int, launchErr error) ifc.Execution {
+	execution.ScheduleName = schedule.Name
+	execution.WorkflowName = schedule.WorkflowName
+	execution.Status = "RETRYING"
+	execution.Attempts = attempts
+	execution.Error = launchErr.Error()
+	var err error
+	if execution.ID == "" {
+		execution.ID = uuid.NewString()
+		err = Configuration.Db.InsertExecution(execution)
+	} else {
+		err = Configuration.Db.UpdateExecution(execution)
+	}
+	if err != nil {
+		logrus.Errorf("Error saving execution of schedule %s. err=%s", schedule.Name, err)
+	}
+	activeRetriesMutex.Lock()
+	activeRetries[execution.ID] = true
+	activeRetriesMutex.Unlock()
+	return execution
+}
+
+// isStillRetrying reloads the execution and returns true if it is still RETRYING
+func isStillRetrying(execution ifc.Execution) (bool, error) {
+	executions, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{ScheduleName: execution.ScheduleName, Status: "RETRYING"}, "", 0)
+	if err != nil {
+		return false, fmt.Errorf("Error finding retried launches. err=%s", err)
+	}
+	for _, retrying := range executions {
+		if retrying.ID == execution.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isRetrying returns true while launch of the execution is retried by this replica
+func isRetrying(executionID string) bool {
+	activeRetriesMutex.Lock()
+	defer activeRetriesMutex.Unlock()
+	return activeRetries[executionID]
+}
+
+// failInterruptedRetries records RETRYING executions not retried by this replica (e.g. left by a previous leader
+// that stopped) as FAILED, so that they do not hold back concurrency policy of their schedules
+func failInterruptedRetries() error {
+	executions, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{Status: "RETRYING"}, "", 0)
+	if err != nil {
+		return fmt.Errorf("Error finding retried launches. err=%s", err)
+	}
+	for _, execution := range executions {
+		if isRetrying(execution.ID) {
+			continue
+		}
+		logrus.Infof("Schedule %s: Launch retries of trigger from %s were interrupted", execution.ScheduleName, execution.FireTime)
+		now := time.Now()
+		execution.Status = "FAILED"
+		execution.EndTime = &now
+		execution.Error = fmt.Sprintf("launch retries interrupted. err=%s", execution.Error)
+		err = Configuration.Db.UpdateExecution(execution)
+		if err != nil {
+			logrus.Errorf("Error updating execution of schedule %s. err=%s", execution.ScheduleName, err)
+			continue
+		}
+		schedule, err := Configuration.Db.FindByName(execution.ScheduleName)
+		if err == nil && schedule != nil && schedule.Status == "RETRYING" {
+			err = Configuration.Db.UpdateStatus(schedule.Name, "FAILED")
+		}
+		if err != nil {
+			logrus.Errorf("Error saving Schedule status err=%s", err)
+		}
+	}
+	return nil
+}
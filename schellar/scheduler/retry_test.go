@@ -0,0 +1,281 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestIsRetryableLaunchError(t *testing.T) {
+	closeConnection := func(w http.ResponseWriter, r *http.Request) {
+		connection, _, _ := w.(http.Hijacker).Hijack()
+		connection.Close()
+	}
+	cases := map[string]struct {
+		handler  http.HandlerFunc
+		expected bool
+	}{
+		"internal server error": {statusHandler(http.StatusInternalServerError), true},
+		"bad gateway":           {statusHandler(http.StatusBadGateway), true},
+		"service unavailable":   {statusHandler(http.StatusServiceUnavailable), true},
+		"too many requests":     {statusHandler(http.StatusTooManyRequests), true},
+		"connection closed":     {closeConnection, true},
+		"bad request":           {statusHandler(http.StatusBadRequest), false},
+		"not found":             {statusHandler(http.StatusNotFound), false},
+		"unauthorized":          {statusHandler(http.StatusUnauthorized), false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			setupTest(t, newFakeDB(), c.handler)
+			schedule := newTestSchedule("retry")
+
+			_, err := launchWorkflow(&schedule, time.Now(), TriggerTimer, nil)
+			if err == nil {
+				t.Fatalf("Expected launch error")
+			}
+			if isRetryableLaunchError(err) != c.expected {
+				t.Fatalf("Unexpected retryable=%v of %v", !c.expected, err)
+			}
+		})
+	}
+
+	errs := map[error]bool{
+		&url.Error{Op: "Post", URL: "http://conductor/workflow", Err: timeoutError{}}:         true,
+		fmt.Errorf("%w. err=%s", ErrInvalidTemplate, "function \"unknown\" not defined"):      false,
+		fmt.Errorf("wrapped %w", &conductorStatusError{StatusCode: 503, Message: "Failed"}):   true,
+		fmt.Errorf("wrapped %w", &conductorStatusError{StatusCode: 409, Message: "Conflict"}): false,
+	}
+	for err, expected := range errs {
+		if isRetryableLaunchError(err) != expected {
+			t.Errorf("Unexpected retryable=%v of %v", !expected, err)
+		}
+	}
+}
+
+func TestLaunchRefusedConnectionIsRetryable(t *testing.T) {
+	setupTest(t, newFakeDB(), nil)
+	server := httptest.NewServer(statusHandler(http.StatusOK))
+	Configuration.ConductorURL = server.URL
+	server.Close()
+	schedule := newTestSchedule("retry")
+
+	_, err := launchWorkflow(&schedule, time.Now(), TriggerTimer, nil)
+	if err == nil || !isRetryableLaunchError(err) {
+		t.Fatalf("Expected retryable error, got %v", err)
+	}
+}
+
+func statusHandler(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	cases := map[string]struct {
+		policy   ifc.RetryPolicy
+		expected []time.Duration
+	}{
+		"doubling": {ifc.RetryPolicy{MaxAttempts: 5, InitialDelaySeconds: 10, Multiplier: 2, MaxDelaySeconds: 300},
+			[]time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second}},
+		"fractional multiplier": {ifc.RetryPolicy{MaxAttempts: 4, InitialDelaySeconds: 4, Multiplier: 1.5, MaxDelaySeconds: 300},
+			[]time.Duration{4 * time.Second, 6 * time.Second, 9 * time.Second}},
+		"capped": {ifc.RetryPolicy{MaxAttempts: 5, InitialDelaySeconds: 30, Multiplier: 3, MaxDelaySeconds: 100},
+			[]time.Duration{30 * time.Second, 90 * time.Second, 100 * time.Second, 100 * time.Second}},
+		"constant": {ifc.RetryPolicy{MaxAttempts: 4, InitialDelaySeconds: 5, Multiplier: 1, MaxDelaySeconds: 300},
+			[]time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second}},
+		"multiplier below 1": {ifc.RetryPolicy{MaxAttempts: 3, InitialDelaySeconds: 5, Multiplier: 0.5},
+			[]time.Duration{5 * time.Second, 5 * time.Second}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			for i, expected := range c.expected {
+				if delay := c.policy.Delay(i + 1); delay != expected {
+					t.Fatalf("Unexpected delay after attempt %d: %s != %s", i+1, delay, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestFireScheduleRetriesLaunch(t *testing.T) {
+	cases := map[string]struct {
+		launchStatuses   []int
+		maxAttempts      int
+		expectedStatus   string
+		expectedAttempts int
+	}{
+		"launched after retries": {[]int{503, 500}, 3, "RUNNING", 3},
+		"all attempts failed":    {[]int{503, 503}, 2, "FAILED", 2},
+		"not retryable":          {[]int{503, 404}, 3, "FAILED", 2},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			conductor.launchStatuses = c.launchStatuses
+			setupTest(t, db, conductor.ServeHTTP)
+			schedule := newTestSchedule("retry")
+			schedule.Status = ""
+			schedule.ConcurrencyPolicy = ifc.ConcurrencyForbid
+			schedule.RetryPolicy = ifc.RetryPolicy{MaxAttempts: c.maxAttempts, InitialDelaySeconds: 1, Multiplier: 1, MaxDelaySeconds: 1}
+			db.Insert(schedule)
+
+			_, err := FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, false)
+			if !errors.Is(err, ErrLaunchRetrying) {
+				t.Fatalf("Expected launch retried in background, got %v", err)
+			}
+			execution := db.executionsOf(schedule.Name)[0]
+			if execution.Status != "RETRYING" || execution.Attempts != 1 || execution.Error == "" {
+				t.Fatalf("Expected RETRYING execution persisted before retrying, got %+v", execution)
+			}
+			if db.schedule(schedule.Name).Status != "RETRYING" {
+				t.Errorf("Unexpected schedule status %s", db.schedule(schedule.Name).Status)
+			}
+			// retrying launch counts as running workflow
+			_, err = FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, false)
+			if !errors.Is(err, ErrTriggerSkipped) || errors.Is(err, ErrLaunchRetrying) {
+				t.Errorf("Expected trigger skipped while launch is retried, got %v", err)
+			}
+
+			waitFor(t, func() bool { return db.schedule(schedule.Name).Status == c.expectedStatus })
+			executions := db.executionsOf(schedule.Name)
+			if len(executions) != 1 || executions[0].Status != c.expectedStatus || executions[0].Attempts != c.expectedAttempts {
+				t.Fatalf("Unexpected executions %+v", executions)
+			}
+			waitFor(t, func() bool { return !isRetrying(executions[0].ID) })
+		})
+	}
+}
+
+func TestFireScheduleDoesNotRetryRejectedLaunch(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	conductor.launchStatuses = []int{http.StatusBadRequest}
+	setupTest(t, db, conductor.ServeHTTP)
+	schedule := newTestSchedule("retry")
+	schedule.RetryPolicy = ifc.RetryPolicy{MaxAttempts: 3, InitialDelaySeconds: 1, Multiplier: 1, MaxDelaySeconds: 1}
+	db.Insert(schedule)
+
+	_, err := FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, false)
+	if err == nil || errors.Is(err, ErrTriggerSkipped) {
+		t.Fatalf("Expected launch error, got %v", err)
+	}
+	execution := db.executionsOf(schedule.Name)[0]
+	if execution.Status != "FAILED" || execution.Attempts != 1 {
+		t.Fatalf("Unexpected execution %+v", execution)
+	}
+}
+
+func TestFailInterruptedRetries(t *testing.T) {
+	db := newFakeDB()
+	setupTest(t, db, nil)
+	schedule := newTestSchedule("retry")
+	schedule.Status = "RETRYING"
+	db.Insert(schedule)
+	db.InsertExecution(ifc.Execution{
+		ID:           uuid.NewString(),
+		ScheduleName: schedule.Name,
+		FireTime:     time.Now().Add(-time.Minute),
+		Trigger:      TriggerTimer,
+		Status:       "RETRYING",
+		Attempts:     2,
+		Error:        "Failed to create new workflow instance. status=503",
+		WorkflowName: "workflow",
+	})
+
+	err := failInterruptedRetries()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	execution := db.executionsOf(schedule.Name)[0]
+	if execution.Status != "FAILED" || execution.EndTime == nil || execution.Attempts != 2 {
+		t.Errorf("Expected interrupted retries failed, got %+v", execution)
+	}
+	if db.schedule(schedule.Name).Status != "FAILED" {
+		t.Errorf("Unexpected schedule status %s", db.schedule(schedule.Name).Status)
+	}
+}
+
+func TestRetryLaunchAbandoned(t *testing.T) {
+	cases := map[string]func(db *fakeDB, execution ifc.Execution){
+		"leadership lost": func(db *fakeDB, execution ifc.Execution) {
+			leaderMutex.Lock()
+			leader = false
+			leaderMutex.Unlock()
+		},
+		"execution failed by new leader": func(db *fakeDB, execution ifc.Execution) {
+			execution.Status = "FAILED"
+			db.UpdateExecution(execution)
+		},
+	}
+	for name, interrupt := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			conductor.launchStatuses = []int{http.StatusServiceUnavailable}
+			setupTest(t, db, conductor.ServeHTTP)
+			schedule := newTestSchedule("retry")
+			schedule.RetryPolicy = ifc.RetryPolicy{MaxAttempts: 3, InitialDelaySeconds: 1, Multiplier: 1, MaxDelaySeconds: 1}
+			db.Insert(schedule)
+
+			_, err := FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, false)
+			if !errors.Is(err, ErrLaunchRetrying) {
+				t.Fatalf("Expected launch retried in background, got %v", err)
+			}
+			execution := db.executionsOf(schedule.Name)[0]
+			interrupt(db, execution)
+			expectedStatus := db.executionsOf(schedule.Name)[0].Status
+
+			waitFor(t, func() bool { return !isRetrying(execution.ID) })
+			if conductor.launchCount() != 0 {
+				t.Errorf("Expected no workflow launched after retry was abandoned, got %v", conductor.launched)
+			}
+			if actual := db.executionsOf(schedule.Name)[0]; actual.Status != expectedStatus || actual.Attempts != 1 {
+				t.Errorf("Expected abandoned execution not recorded, got %+v", actual)
+			}
+		})
+	}
+}
+
+func TestRetryLaunchFailureRecountsRunningWorkflows(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	conductor.launchStatuses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	setupTest(t, db, conductor.ServeHTTP)
+	schedule := newTestSchedule("retry")
+	schedule.RetryPolicy = ifc.RetryPolicy{MaxAttempts: 2, InitialDelaySeconds: 1, Multiplier: 1, MaxDelaySeconds: 1}
+	db.Insert(schedule)
+	addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-time.Hour))
+
+	_, err := FireSchedule(schedule.Name, time.Now(), TriggerTimer, nil, true)
+	if !errors.Is(err, ErrLaunchRetrying) {
+		t.Fatalf("Expected launch retried in background, got %v", err)
+	}
+	if db.schedule(schedule.Name).Status != "RUNNING" {
+		t.Fatalf("Expected schedule with running workflow kept RUNNING, got %s", db.schedule(schedule.Name).Status)
+	}
+	// the running workflow finishes while the launch is retried
+	db.updateExecution("wf-old", func(execution *ifc.Execution) { execution.Status = "COMPLETED" })
+
+	retried := db.executionsOf(schedule.Name)[1]
+	waitFor(t, func() bool { return !isRetrying(retried.ID) })
+	if executionStatuses(db, schedule.Name)["/"+TriggerTimer] != "FAILED" {
+		t.Fatalf("Expected failed launch recorded, got %v", executionStatuses(db, schedule.Name))
+	}
+	if db.schedule(schedule.Name).Status != "FAILED" {
+		t.Errorf("Expected schedule FAILED after the last launch failed, got %s", db.schedule(schedule.Name).Status)
+	}
+}
@@ -1,38 +1,76 @@
 package scheduler
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/frinx/schellar/ifc"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	TriggerTimer    = "TIMER"
+	TriggerManual   = "MANUAL"
+	TriggerMisfire  = "MISFIRE"
+	TriggerRelaunch = "RELAUNCH"
+	TriggerUpstream = "UPSTREAM"
+)
+
 var (
 	scheduledRoutineHashes = make(map[string]*cron.Cron)
+	timersMutex            sync.Mutex
+	ErrTriggerSkipped      = errors.New("trigger skipped")
+	// serializes refreshes of running schedules by the periodic check and by workflow events
+	refreshMutex sync.Mutex
+	// long running workflows already reported, so that they are reported only once
+	warnedWorkflows = make(map[string]bool)
 )
 
 func StartScheduler() error {
-	err := PrepareTimers()
-	if err != nil {
-		return err
+	if Configuration.HAEnabled {
+		logrus.Infof("Instance %s: Starting in HA mode, waiting for leadership", Configuration.InstanceID)
+		go runLeaderElection()
+		return nil
 	}
-	go CheckRunningWorkflows()
-	return nil
+	return becomeLeader()
 }
 
+// PrepareTimers starts timers of enabled schedules and stops the others. Paused schedules whose pause ended are resumed first.
+// Replicas that are not the leader stop all timers. Next fire times of changed schedules are refreshed by RefreshNextFireTime.
 func PrepareTimers() error {
 	logrus.Debugf("Refreshing timers according to active schedules")
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
 
-	activeSchedules, err := Configuration.Db.FindAllByEnabled(true)
-	if err != nil {
-		return err
+	activeSchedules := make([]ifc.Schedule, 0)
+	if IsLeader() {
+		resumed, err := resumeExpiredPauses()
+		if err != nil {
+			return err
+		}
+		for _, schedule := range resumed {
+			RefreshNextFireTime(schedule.Name)
+		}
+		enabledSchedules, err := Configuration.Db.FindAllByEnabled(true)
+		if err != nil {
+			return err
+		}
+		for _, schedule := range enabledSchedules {
+			// fired by their upstream schedules instead of a timer
+			if !schedule.IsUpstreamTriggered() {
+				activeSchedules = append(activeSchedules, schedule)
+			}
+		}
 	}
 
 	//activate go routines for schedules that weren't activated yet
 	for _, activeSchedule := range activeSchedules {
 		isScheduled := false
-		activeRoutineHash := fmt.Sprintf("%s|%s)", activeSchedule.Name, activeSchedule.CronString)
+		activeRoutineHash := routineHash(activeSchedule)
 		for hashRoutine := range scheduledRoutineHashes {
 			if activeRoutineHash == hashRoutine {
 				isScheduled = true
@@ -51,7 +89,7 @@ func PrepareTimers() error {
 	for hashRoutine, cronJob := range scheduledRoutineHashes {
 		isActive := false
 		for _, activeSchedule := range activeSchedules {
-			activeRoutineHash := fmt.Sprintf("%s|%s)", activeSchedule.Name, activeSchedule.CronString)
+			activeRoutineHash := routineHash(activeSchedule)
 			if hashRoutine == activeRoutineHash {
 				isActive = true
 				break
@@ -75,77 +113,157 @@ func LaunchSchedule(scheduleName string) error {
 		return err
 	}
 
+	cronSchedule, err := schedule0.ParseCron()
+	if err != nil {
+		logrus.Errorf("Couldn't parse cron string of schedule %s. err=%s", scheduleName, err)
+		return err
+	}
+
 	c := cron.New()
-	logrus.Infof("Schedule %s: Creating timer. cron=%s. workflow=%s", schedule0.Name, schedule0.CronString, schedule0.WorkflowName)
-	c.AddFunc(schedule0.CronString, func() {
+	hash := routineHash(*schedule0)
+	logrus.Infof("Schedule %s: Creating timer. cron=%s. cronFormat=%s. timeZone=%s. jitter=%ds (%s). workflow=%s",
+		schedule0.Name, schedule0.CronString, schedule0.CronFormat, schedule0.TimeZone,
+		schedule0.JitterSeconds, schedule0.JitterMode, schedule0.WorkflowName)
+	c.Schedule(cronSchedule, cron.FuncJob(func() {
 		logrus.Debugf("Processing timer trigger for schedule %s", scheduleName)
-
-		schedule, err := Configuration.Db.FindByName(scheduleName)
-		if err != nil {
-			logrus.Errorf("Couldn't get schedule %s. err=%s", scheduleName, err)
-			return
+		fireTime := time.Now()
+		if delay := schedule0.StartDelay(); delay > 0 {
+			logrus.Debugf("Schedule %s: Delaying timer trigger by %s", scheduleName, delay)
+			time.Sleep(delay)
+			// the trigger is left to misfire handling of the next leader
+			if !isTimerActive(hash, c) {
+				logrus.Debugf("Schedule %s: Timer stopped while delaying trigger from %s", scheduleName, fireTime)
+				return
+			}
 		}
-
-		isBefore := false
-		if schedule.ToDate == nil || time.Now().Before(*schedule.ToDate) {
-			isBefore = true
+		_, err := FireSchedule(scheduleName, fireTime, TriggerTimer, nil, false)
+		if errors.Is(err, ErrTriggerSkipped) {
+			logrus.Debugf("%s", err)
+		} else if err != nil {
+			logrus.Errorf("Error processing timer trigger for schedule %s. err=%s", scheduleName, err)
 		}
-		isAfter := false
-		if schedule.FromDate == nil || time.Now().After(*schedule.FromDate) {
-			isAfter = true
+		err = Configuration.Db.UpdateLastFireTime(scheduleName, fireTime)
+		if err != nil {
+			logrus.Errorf("Error saving last fire time of schedule %s. err=%s", scheduleName, err)
 		}
-		if isBefore && isAfter {
+		RefreshNextFireTime(scheduleName)
+	}))
+	scheduledRoutineHashes[hash] = c
+	go c.Start()
+	return nil
+}
 
-			runningWorkflows, err2 := findWorkflows(schedule.WorkflowName, schedule.Name, true)
-			if err2 != nil {
-				logrus.Errorf("Error finding currently running workflows. err=%s", err2)
-				return
-			}
+// routineHash identifies timer of the schedule, timer is recreated when any of its parts changes
+func routineHash(schedule ifc.Schedule) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%d|%s", schedule.Name, schedule.CronFormat, schedule.CronString, schedule.TimeZone,
+		schedule.JitterSeconds, schedule.JitterMode)
+}
 
-			runningTotalHits := int(runningWorkflows["totalHits"].(float64))
-
-			scheduleStatus := "RUNNING"
-			if runningTotalHits > 0 {
-				if !schedule.ParallelRuns {
-					wresults := runningWorkflows["results"]
-					if wresults != nil {
-						wf0 := wresults.([]interface{})[0]
-						wf1 := wf0.(map[string]interface{})
-						workflowID := wf1["workflowId"]
-						logrus.Debugf("Schedule %s trigger skipped. Previous workflow id (%s) has not finished yet", schedule.Name, workflowID)
-						return
-					}
-				}
-				logrus.Infof("Schedule %s: Launching concurrent workflow (%s). count=%d", schedule.Name, schedule.WorkflowName, runningTotalHits)
-			}
+// isTimerActive returns true while the timer is running on the leader and was not replaced
+func isTimerActive(hash string, c *cron.Cron) bool {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+	return scheduledRoutineHashes[hash] == c && IsLeader()
+}
 
-			logrus.Debugf("Launching workflow '%s' for schedule '%s'", schedule.WorkflowName, scheduleName)
-			err := launchWorkflow(scheduleName)
-			if err != nil {
-				logrus.Errorf("Error launching Workflow err=%s", err)
-				return
-			}
+// FireSchedule launches a new workflow of the schedule and returns its workflowId.
+// The trigger is skipped with ErrTriggerSkipped when fireTime is not within schedule activation dates,
+// when calendars of the schedule forbid it (recorded as SKIPPED execution), when dependencies of the schedule
+// are not satisfied (except manual triggers) or when concurrency policy of the schedule does not allow another running workflow (unless ignoreParallelRuns).
+// Triggers exceeding launch limits are deferred with ErrTriggerPending, manual triggers wait for them instead.
+// Failed launches retried in background return ErrLaunchRetrying.
+func FireSchedule(scheduleName string, fireTime time.Time, trigger string, inputOverride map[string]interface{}, ignoreParallelRuns bool) (string, error) {
+	schedule, err := Configuration.Db.FindByName(scheduleName)
+	if err != nil {
+		return "", fmt.Errorf("Couldn't get schedule %s. err=%s", scheduleName, err)
+	}
+	if schedule == nil {
+		return "", fmt.Errorf("Schedule %s not found", scheduleName)
+	}
+
+	execution := ifc.Execution{FireTime: fireTime, Trigger: trigger}
+	runningExecutions, err := checkTrigger(schedule, execution, ignoreParallelRuns)
+	if err != nil {
+		return "", err
+	}
+
+	release, err := acquireLaunchSlot(schedule, fireTime, trigger)
+	if err != nil {
+		return "", err
+	}
+
+	logrus.Debugf("Launching workflow '%s' for schedule '%s'. trigger=%s", schedule.WorkflowName, scheduleName, trigger)
+	return launchExecution(schedule, execution, len(runningExecutions), inputOverride, release)
+}
 
-			logrus.Debugf("Updating Schedule status. name=%s. status=%s", scheduleName, "RUNNING")
-			err0 := Configuration.Db.UpdateStatus(scheduleName, scheduleStatus)
-			if err0 != nil {
-				logrus.Errorf("Error saving Schedule status err=%s", err0)
+// checkTrigger applies activation dates, calendars, dependencies and concurrency policy of the schedule to the trigger
+// of the execution, both when it is fired and when it is launched later after waiting for launch limits or
+// running workflows. Activation dates and calendars of such waiting (persisted) executions must also allow the launch now.
+// Skipped triggers of executions that were not persisted yet are recorded only when calendars forbid them.
+// Returns running executions of the schedule or (wrapped) ErrTriggerSkipped when the workflow must not be launched.
+func checkTrigger(schedule *ifc.Schedule, execution ifc.Execution, ignoreParallelRuns bool) ([]ifc.Execution, error) {
+	checkTimes := []time.Time{execution.FireTime}
+	if execution.ID != "" {
+		checkTimes = append(checkTimes, time.Now())
+	}
+	for _, checkTime := range checkTimes {
+		if (schedule.ToDate != nil && !checkTime.Before(*schedule.ToDate)) ||
+			(schedule.FromDate != nil && !checkTime.After(*schedule.FromDate)) {
+			return nil, fmt.Errorf("%w: schedule %s active, but not within activation date", ErrTriggerSkipped, schedule.Name)
+		}
+
+		reason, err := calendarSkipReason(schedule, checkTime)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			if execution.ID == "" {
+				recordSkip(schedule.Name, execution.FireTime, execution.Trigger, reason)
+			} else {
+				calendarSkipsCounter.WithLabelValues(schedule.Name).Inc()
 			}
+			return nil, fmt.Errorf("%w: schedule %s %s", ErrTriggerSkipped, schedule.Name, reason)
+		}
+	}
 
-		} else {
-			logrus.Debugf("Schedule %s active, but not within activation date", scheduleName)
+	if execution.Trigger != TriggerManual {
+		err := checkDependencies(schedule, time.Now())
+		if err != nil {
+			dependencySkipsCounter.WithLabelValues(schedule.Name).Inc()
+			return nil, fmt.Errorf("%w: schedule %s dependency not satisfied, %s", ErrTriggerSkipped, schedule.Name, err)
 		}
+	}
 
-	})
-	routineHash := fmt.Sprintf("%s|%s)", schedule0.Name, schedule0.CronString)
-	scheduledRoutineHashes[routineHash] = c
-	go c.Start()
-	return nil
+	runningExecutions, err := findRunningExecutions(schedule.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Error finding currently running workflows. err=%s", err)
+	}
+
+	if ignoreParallelRuns {
+		if len(runningExecutions) > 0 {
+			logrus.Infof("Schedule %s: Launching concurrent workflow (%s) ignoring concurrency policy. count=%d",
+				schedule.Name, schedule.WorkflowName, len(runningExecutions))
+		}
+		return runningExecutions, nil
+	}
+	err = applyConcurrencyPolicy(schedule, execution, runningExecutions)
+	if err != nil {
+		return nil, err
+	}
+	return runningExecutions, nil
 }
 
-func CheckRunningWorkflows() {
+// CheckRunningWorkflows periodically updates status of schedules with running workflows until stop is closed.
+// With workflow events enabled it only reconciles schedules whose events were missed.
+func CheckRunningWorkflows(stop <-chan struct{}) {
 	logrus.Debugf("Starting to check running workflow status")
 	for {
+		select {
+		case <-stop:
+			logrus.Debugf("Stopping to check running workflow status")
+			return
+		default:
+		}
 		startTime := time.Now()
 		schedules, err0 := Configuration.Db.FindByStatus("RUNNING")
 
@@ -158,69 +276,236 @@ func CheckRunningWorkflows() {
 			logrus.Debugf("Checking running workflows on Conductor...")
 		}
 		for _, schedule := range schedules {
-			runningWorkflows, err := findWorkflows(schedule.WorkflowName, schedule.Name, true)
-			if err != nil {
-				logrus.Errorf("Error finding workflows for schedule %s. err=%s", schedule.Name, err)
-				continue
-			}
-			finishedWorkflows, err := findWorkflows(schedule.WorkflowName, schedule.Name, false)
-			if err != nil {
-				logrus.Errorf("Error finding workflows for schedule %s. err=%s", schedule.Name, err)
-				continue
-			}
-			runningTotalHits := int(runningWorkflows["totalHits"].(float64))
-			finishedTotalHits := int(finishedWorkflows["totalHits"].(float64))
-
-			logrus.Debugf("Running workflows hits for schedule %s: %d", schedule.Name, runningTotalHits)
-			logrus.Debugf("Finished workflows hits for schedule %s: %d", schedule.Name, finishedTotalHits)
-
-			scheduleStatus := "RUNNING"
-			var wfoutput map[string]interface{}
-			if runningTotalHits == 0 {
-				if finishedTotalHits == 0 {
-					logrus.Errorf("No workflows found for schedule %s, but it is in state RUNNING", schedule.Name)
-					continue
-				} else {
-					wf0 := finishedWorkflows["results"].([]interface{})[0]
-					wf1 := wf0.(map[string]interface{})
-					wf2, err := getWorkflowInstance(wf1["workflowId"].(string))
-					if err != nil {
-						logrus.Errorf("Could not get workflow instance. err=%s", err)
-						continue
-					}
-					scheduleStatus = wf2["status"].(string)
-					out, exists := wf2["output"]
-					if exists {
-						wfoutput = out.(map[string]interface{})
-					}
-				}
+			refreshSchedule(schedule)
+		}
+
+		elapsedTime := time.Now().Sub(startTime)
+		remainingSleep := float64(checkIntervalSeconds()) - elapsedTime.Seconds()
+		if remainingSleep > 0 {
+			logrus.Debugf("Sleeping for %d seconds...", int(remainingSleep))
+			select {
+			case <-stop:
+			case <-time.After(time.Duration(remainingSleep) * time.Second):
 			}
+		}
+	}
+}
+
+// checkIntervalSeconds returns how often running workflows are polled, less often when workflow events deliver their completion
+func checkIntervalSeconds() int {
+	if Configuration.EventsEnabled {
+		return Configuration.ReconcileIntervalSeconds
+	}
+	return Configuration.CheckIntervalSeconds
+}
+
+// refreshSchedule updates executions, status and workflow context of the schedule from state
+// of its workflows tracked as running in Conductor
+func refreshSchedule(schedule ifc.Schedule) {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	runningExecutions, err := findRunningExecutions(schedule.Name)
+	if err != nil {
+		logrus.Errorf("Error finding workflows for schedule %s. err=%s", schedule.Name, err)
+		return
+	}
 
-			logrus.Debugf("Schedule status is %s", scheduleStatus)
-			if scheduleStatus != schedule.Status {
-				logrus.Infof("Schedule %s: Changing status to %s", schedule.Name, scheduleStatus)
+	// executions are ordered from the most recent one, so the first finished workflow is the latest
+	runningCount := 0
+	retryingCount := 0
+	terminatedCount := 0
+	finishedCount := 0
+	longRunning := make([]ifc.Execution, 0)
+	var lastFinished map[string]interface{}
+	for _, execution := range runningExecutions {
+		if execution.Status == "RETRYING" {
+			// no workflow yet, launch of the execution finishes its record
+			retryingCount++
+			continue
+		}
+		wf, err := getWorkflowInstance(execution.WorkflowID)
+		if err != nil {
+			logrus.Errorf("Could not get workflow instance. err=%s", err)
+			runningCount++
+			continue
+		}
+		if GetStringValue(wf, "status", "RUNNING") == "RUNNING" && schedule.IsOverrunning(execution, time.Now()) {
+			terminated := terminateOverrunningWorkflow(schedule, execution)
+			if terminated != nil {
+				wf = terminated
+				terminatedCount++
 			}
-			schedule.Status = scheduleStatus
-			if len(wfoutput) > 0 {
-				logrus.Debugf("Adding last workflow output to schedule context. output=%s, workflowContext=%s",
-					wfoutput, schedule.WorkflowContext)
-				schedule.WorkflowContext["lastExecution"] = wfoutput
+		}
+		if GetStringValue(wf, "status", "RUNNING") == "RUNNING" {
+			runningCount++
+			if execution.StartTime != nil && time.Since(*execution.StartTime) > schedule.WarningThreshold() {
+				longRunning = append(longRunning, execution)
 			}
-			err0 = Configuration.Db.UpdateStatusAndWorkflowContext(schedule)
-			if err0 != nil {
-				logrus.Errorf("Error updating schedule %s to status %s. err=%s", schedule.Name, scheduleStatus, err0)
+			continue
+		}
+		delete(warnedWorkflows, execution.WorkflowID)
+		completed := recordCompletion(wf)
+		if completed != nil && recoverFailedWorkflow(schedule, *completed, wf) {
+			runningCount++
+			continue
+		}
+		finishedCount++
+		if lastFinished == nil {
+			lastFinished = wf
+		}
+	}
+
+	if schedule.Concurrency() == ifc.ConcurrencyQueue {
+		launched, retrying := launchQueuedTriggers(schedule, runningCount+retryingCount)
+		runningCount += launched
+		retryingCount += retrying
+	}
+
+	logrus.Debugf("Running workflows for schedule %s: %d", schedule.Name, runningCount)
+	checkLongRunningWorkflows(schedule, longRunning, warnedWorkflows)
+
+	scheduleStatus := "RUNNING"
+	if runningCount == 0 && retryingCount > 0 {
+		scheduleStatus = "RETRYING"
+	} else if runningCount == 0 {
+		if lastFinished == nil {
+			logrus.Warnf("No running workflows tracked for schedule %s, but it is in state RUNNING", schedule.Name)
+			scheduleStatus = "UNKNOWN"
+		} else {
+			scheduleStatus = lastFinished["status"].(string)
+			if scheduleStatus == "COMPLETED" || schedule.UpdateContextOnFailure {
+				mergeWorkflowOutput(&schedule, lastFinished)
 			}
 		}
+	}
 
-		elapsedTime := time.Now().Sub(startTime)
-		remainingSleep := float64(Configuration.CheckIntervalSeconds) - elapsedTime.Seconds()
-		if remainingSleep > 0 {
-			logrus.Debugf("Sleeping for %d seconds...", int(remainingSleep))
-			time.Sleep(time.Duration(remainingSleep) * time.Second)
+	logrus.Debugf("Schedule status is %s", scheduleStatus)
+	if scheduleStatus != schedule.Status {
+		logrus.Infof("Schedule %s: Changing status to %s", schedule.Name, scheduleStatus)
+	}
+	schedule.Status = scheduleStatus
+	err = Configuration.Db.UpdateStatusAndWorkflowContext(schedule)
+	if err != nil {
+		logrus.Errorf("Error updating schedule %s to status %s. err=%s", schedule.Name, scheduleStatus, err)
+	}
+
+	if finishedCount > 0 {
+		triggerDownstream(schedule.Name)
+	}
+
+	if terminatedCount > 0 && schedule.OnOverrun == ifc.OverrunTerminateAndRelaunch {
+		logrus.Infof("Schedule %s: Relaunching workflow after overrun", schedule.Name)
+		_, err = FireSchedule(schedule.Name, time.Now(), TriggerRelaunch, nil, false)
+		if errors.Is(err, ErrTriggerSkipped) {
+			logrus.Debugf("%s", err)
+		} else if err != nil {
+			logrus.Errorf("Error relaunching workflow of schedule %s. err=%s", schedule.Name, err)
 		}
 	}
 }
 
+// checkLongRunningWorkflows reports workflows running longer than checkWarningSeconds of the schedule
+// and marks the schedule with WARNING condition while there are any
+func checkLongRunningWorkflows(schedule ifc.Schedule, longRunning []ifc.Execution, warnedWorkflows map[string]bool) {
+	longRunningWorkflowsGauge.WithLabelValues(schedule.Name).Set(float64(len(longRunning)))
+	for _, execution := range longRunning {
+		if warnedWorkflows[execution.WorkflowID] {
+			continue
+		}
+		warnedWorkflows[execution.WorkflowID] = true
+		runningSeconds := int(time.Since(*execution.StartTime).Seconds())
+		logrus.WithFields(logrus.Fields{
+			"event":               EventLongRunningWorkflow,
+			"schedule":            schedule.Name,
+			"workflowId":          execution.WorkflowID,
+			"runningSeconds":      runningSeconds,
+			"checkWarningSeconds": int(schedule.WarningThreshold().Seconds()),
+		}).Warn("Workflow is running longer than checkWarningSeconds")
+		longRunningWorkflowWarningsCounter.WithLabelValues(schedule.Name).Inc()
+		notify(Notification{
+			Event:        EventLongRunningWorkflow,
+			ScheduleName: schedule.Name,
+			WorkflowID:   execution.WorkflowID,
+			Message:      fmt.Sprintf("Workflow has been running for %d seconds", runningSeconds),
+		})
+	}
+
+	condition := ifc.ConditionOK
+	conditionMessage := ""
+	if len(longRunning) > 0 {
+		// executions are ordered from the most recent one
+		oldest := longRunning[len(longRunning)-1]
+		condition = ifc.ConditionWarning
+		conditionMessage = fmt.Sprintf("%d workflow(s) running longer than %d seconds, oldest workflowId=%s",
+			len(longRunning), int(schedule.WarningThreshold().Seconds()), oldest.WorkflowID)
+	}
+	if condition == schedule.Condition && conditionMessage == schedule.ConditionMessage {
+		return
+	}
+	if condition != schedule.Condition {
+		logrus.Infof("Schedule %s: Changing condition to %s", schedule.Name, condition)
+	}
+	err := Configuration.Db.UpdateCondition(schedule.Name, condition, conditionMessage)
+	if err != nil {
+		logrus.Errorf("Error updating schedule %s to condition %s. err=%s", schedule.Name, condition, err)
+	}
+}
+
+// terminateOverrunningWorkflow terminates the workflow that exceeded maxRunDuration of the schedule.
+// Returns the terminated workflow instance, nil when the termination failed and should be retried on next check.
+func terminateOverrunningWorkflow(schedule ifc.Schedule, execution ifc.Execution) map[string]interface{} {
+	runningSeconds := int(time.Since(*execution.StartTime).Seconds())
+	reason := fmt.Sprintf("Terminated by schellar, running for %d seconds exceeded maxRunDuration %d seconds of schedule %s",
+		runningSeconds, schedule.MaxRunDuration, schedule.Name)
+	err := terminateWorkflow(execution.WorkflowID, reason)
+	if err != nil {
+		logrus.Errorf("Error terminating overrunning workflow of schedule %s. err=%s", schedule.Name, err)
+		return nil
+	}
+	logrus.WithFields(logrus.Fields{
+		"event":          EventOverrunTerminated,
+		"schedule":       schedule.Name,
+		"workflowId":     execution.WorkflowID,
+		"runningSeconds": runningSeconds,
+		"maxRunDuration": schedule.MaxRunDuration,
+	}).Warn("Workflow terminated for running longer than maxRunDuration")
+	overrunTerminationsCounter.WithLabelValues(schedule.Name).Inc()
+	notify(Notification{
+		Event:        EventOverrunTerminated,
+		ScheduleName: schedule.Name,
+		WorkflowID:   execution.WorkflowID,
+		Message:      reason,
+	})
+
+	wf, err := getWorkflowInstance(execution.WorkflowID)
+	if err != nil {
+		logrus.Errorf("Could not get terminated workflow instance. err=%s", err)
+		return map[string]interface{}{
+			"workflowId":            execution.WorkflowID,
+			"status":                "TERMINATED",
+			"reasonForIncompletion": reason,
+		}
+	}
+	return wf
+}
+
+// findRunningExecutions returns executions of the schedule with running workflows, including the ones whose launch
+// is being retried, ordered from the most recent one
+func findRunningExecutions(scheduleName string) ([]ifc.Execution, error) {
+	running, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{ScheduleName: scheduleName, Status: "RUNNING"}, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	retrying, err := Configuration.Db.FindExecutions(ifc.ExecutionFilter{ScheduleName: scheduleName, Status: "RETRYING"}, "", 0)
+	if err != nil || len(retrying) == 0 {
+		return running, err
+	}
+	executions := append(running, retrying...)
+	sort.SliceStable(executions, func(i, j int) bool { return executions[i].FireTime.After(executions[j].FireTime) })
+	return executions, nil
+}
+
 func GetStringValue(m map[string]interface{}, keyName string, defaultValue string) string {
 	v, exists := m[keyName]
 	if !exists {
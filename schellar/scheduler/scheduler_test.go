@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+	"github.com/google/uuid"
+)
+
+func newTestSchedule(name string) ifc.Schedule {
+	return ifc.Schedule{
+		Name:          name,
+		Enabled:       true,
+		Status:        "RUNNING",
+		WorkflowName:  "workflow",
+		CronString:    "0 0 1 1 *",
+		MisfirePolicy: ifc.MisfireSkip,
+		Condition:     ifc.ConditionOK,
+	}
+}
+
+// addRunningExecution records a workflow of the schedule running in Conductor since startTime
+func addRunningExecution(db *fakeDB, conductor *fakeConductor, scheduleName string, workflowID string, startTime time.Time) {
+	conductor.addWorkflow(workflowID, "RUNNING")
+	db.InsertExecution(ifc.Execution{
+		ID:           uuid.NewString(),
+		ScheduleName: scheduleName,
+		FireTime:     startTime,
+		Trigger:      TriggerTimer,
+		WorkflowID:   workflowID,
+		Status:       "RUNNING",
+		StartTime:    &startTime,
+		Attempts:     1,
+		WorkflowName: "workflow",
+	})
+}
+
+func executionStatuses(db *fakeDB, scheduleName string) map[string]string {
+	statuses := make(map[string]string)
+	for _, execution := range db.executionsOf(scheduleName) {
+		statuses[execution.WorkflowID+"/"+execution.Trigger] = execution.Status
+	}
+	return statuses
+}
+
+func TestRefreshScheduleTerminatesOverrunningWorkflow(t *testing.T) {
+	cases := map[string]struct {
+		onOverrun        string
+		expectedStatus   string
+		expectedLaunches int
+	}{
+		ifc.OverrunNone:                 {ifc.OverrunNone, "RUNNING", 0},
+		ifc.OverrunTerminate:            {ifc.OverrunTerminate, "TERMINATED", 0},
+		ifc.OverrunTerminateAndRelaunch: {ifc.OverrunTerminateAndRelaunch, "RUNNING", 1},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			conductor := newFakeConductor()
+			setupTest(t, db, conductor.ServeHTTP)
+			schedule := newTestSchedule("overrun")
+			schedule.MaxRunDuration = 60
+			schedule.OnOverrun = c.onOverrun
+			db.Insert(schedule)
+			addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-2*time.Minute))
+
+			refreshSchedule(db.schedule(schedule.Name))
+
+			terminated := c.onOverrun != ifc.OverrunNone
+			if terminated != (conductor.status("wf-old") == "TERMINATED") {
+				t.Fatalf("Unexpected workflow status %s", conductor.status("wf-old"))
+			}
+			statuses := executionStatuses(db, schedule.Name)
+			if terminated && statuses["wf-old/TIMER"] != "TERMINATED" {
+				t.Errorf("Expected terminated execution, got %v", statuses)
+			}
+			if conductor.launchCount() != c.expectedLaunches {
+				t.Errorf("Unexpected launches %d", conductor.launchCount())
+			}
+			if c.expectedLaunches > 0 && statuses["wf-1/RELAUNCH"] != "RUNNING" {
+				t.Errorf("Expected relaunched execution, got %v", statuses)
+			}
+			if db.schedule(schedule.Name).Status != c.expectedStatus {
+				t.Errorf("Unexpected schedule status %s", db.schedule(schedule.Name).Status)
+			}
+		})
+	}
+}
+
+func TestRefreshScheduleWithinMaxRunDuration(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	schedule := newTestSchedule("overrun")
+	schedule.MaxRunDuration = 3600
+	schedule.OnOverrun = ifc.OverrunTerminate
+	db.Insert(schedule)
+	addRunningExecution(db, conductor, schedule.Name, "wf-old", time.Now().Add(-2*time.Minute))
+
+	refreshSchedule(db.schedule(schedule.Name))
+
+	if len(conductor.terminated) != 0 || db.schedule(schedule.Name).Status != "RUNNING" {
+		t.Fatalf("Workflow within maxRunDuration terminated %v", conductor.terminated)
+	}
+}
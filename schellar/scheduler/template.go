@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+// ErrInvalidTemplate is returned when workflow context of a schedule cannot be rendered
+var ErrInvalidTemplate = errors.New("invalid workflow context template")
+
+// newTemplateData collects variables of the run fired at fireTime. Executions fired earlier decide
+// its previous fire time and run number, so they are the same for retries and relaunches of the run.
+func newTemplateData(schedule *ifc.Schedule, fireTime time.Time, trigger string) (ifc.TemplateData, error) {
+	data := ifc.TemplateData{
+		ScheduleName:  schedule.Name,
+		Trigger:       trigger,
+		FireTime:      fireTime,
+		LastExecution: make(map[string]interface{}),
+	}
+	if location, err := schedule.Location(); err == nil {
+		data.FireTime = fireTime.In(location)
+	}
+	if lastExecution, ok := schedule.WorkflowContext["lastExecution"].(map[string]interface{}); ok {
+		data.LastExecution = lastExecution
+	}
+
+	// fires skipped by calendars did not run, so the next run continues from the last one that did
+	earlier := ifc.ExecutionFilter{ScheduleName: schedule.Name, FiredBefore: &fireTime, ExcludeStatus: "SKIPPED"}
+	previous, err := Configuration.Db.FindExecutions(earlier, "", 1)
+	if err != nil {
+		return data, fmt.Errorf("Error finding previous execution. err=%s", err)
+	}
+	if len(previous) > 0 {
+		data.PreviousFireTime = previous[0].FireTime.In(data.FireTime.Location())
+	}
+	count, err := Configuration.Db.CountExecutions(earlier)
+	if err != nil {
+		return data, fmt.Errorf("Error counting previous executions. err=%s", err)
+	}
+	data.RunNumber = count + 1
+	return data, nil
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+// Codes of workflow validation errors
+const (
+	ValidationWorkflowNotFound       = "WORKFLOW_NOT_FOUND"
+	ValidationConductorUnavailable   = "CONDUCTOR_UNAVAILABLE"
+	ValidationUnknownInputParameters = "UNKNOWN_INPUT_PARAMETERS"
+)
+
+// workflow context keys added by schellar itself
+var reservedContextKeys = map[string]bool{
+	"scheduleName":  true,
+	"lastExecution": true,
+}
+
+// WorkflowValidationError describes schedule that does not match its workflow definition in Conductor
+type WorkflowValidationError struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+func (e *WorkflowValidationError) Error() string {
+	return e.Message
+}
+
+// ValidateWorkflow checks that workflow of the schedule is defined in Conductor and that workflow context
+// contains only input parameters declared by the definition (if it declares any)
+func ValidateWorkflow(schedule *ifc.Schedule) error {
+	definition, err := getWorkflow(schedule.WorkflowName, schedule.WorkflowVersion)
+	if err != nil {
+		return &WorkflowValidationError{
+			Code:    ValidationConductorUnavailable,
+			Field:   "workflowName",
+			Message: fmt.Sprintf("Cannot verify workflow '%s' version '%s' in Conductor. err=%s", schedule.WorkflowName, schedule.WorkflowVersion, err),
+		}
+	}
+	if definition == nil {
+		return &WorkflowValidationError{
+			Code:    ValidationWorkflowNotFound,
+			Field:   "workflowName",
+			Message: fmt.Sprintf("Workflow '%s' version '%s' is not defined in Conductor", schedule.WorkflowName, schedule.WorkflowVersion),
+		}
+	}
+
+	inputParameters, _ := definition["inputParameters"].([]interface{})
+	if len(inputParameters) == 0 {
+		return nil
+	}
+	declared := make(map[string]bool)
+	for _, parameter := range inputParameters {
+		if name, ok := parameter.(string); ok {
+			declared[name] = true
+		}
+	}
+	unknown := make([]string, 0)
+	for key := range schedule.WorkflowContext {
+		if !declared[key] && !reservedContextKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return &WorkflowValidationError{
+			Code:    ValidationUnknownInputParameters,
+			Field:   "workflowContext",
+			Message: fmt.Sprintf("Workflow '%s' does not declare input parameters %v", schedule.WorkflowName, unknown),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxPendingWebhookEvents limits workflows whose events wait for processing, further events are rejected
+const maxPendingWebhookEvents = 1000
+
+// WebhookEventSource receives workflow events over HTTP, e.g. from Conductor workflow status listener.
+// The request body is a JSON object with workflowId of the changed workflow, other fields are ignored.
+// With EVENTS_SECRET configured the request has to carry it as a bearer token in Authorization header.
+// Events are processed one by one, repeated events of a workflow waiting for processing are merged.
+type WebhookEventSource struct {
+	mutex   sync.Mutex
+	running bool
+	// pending holds workflow ids sent to events and not processed yet
+	pending map[string]bool
+	events  chan string
+}
+
+func NewWebhookEventSource() *WebhookEventSource {
+	return &WebhookEventSource{
+		pending: make(map[string]bool),
+		events:  make(chan string, maxPendingWebhookEvents),
+	}
+}
+
+func (source *WebhookEventSource) Run(handle func(workflowID string), stop <-chan struct{}) {
+	source.mutex.Lock()
+	source.running = true
+	source.mutex.Unlock()
+	logrus.Infof("Accepting workflow events over HTTP")
+
+	for {
+		select {
+		case workflowID := <-source.events:
+			source.mutex.Lock()
+			// events received while the workflow is handled are processed again
+			delete(source.pending, workflowID)
+			source.mutex.Unlock()
+			handle(workflowID)
+		case <-stop:
+			source.mutex.Lock()
+			source.running = false
+			// unprocessed workflows are reconciled by the next leader
+			for len(source.events) > 0 {
+				<-source.events
+			}
+			source.pending = make(map[string]bool)
+			source.mutex.Unlock()
+			logrus.Infof("Stopped accepting workflow events over HTTP")
+			return
+		}
+	}
+}
+
+func (source *WebhookEventSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if secret := Configuration.EventsSecret; secret != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+secret)) != 1 {
+		logrus.Debugf("Unauthorized workflow event from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	source.mutex.Lock()
+	running := source.running
+	source.mutex.Unlock()
+	if !running {
+		// standby replicas leave the workflow to the leader, which reconciles it on the next check
+		http.Error(w, "Not the leader", http.StatusServiceUnavailable)
+		return
+	}
+
+	var event struct {
+		WorkflowID string `json:"workflowId"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&event)
+	if err != nil || event.WorkflowID == "" {
+		logrus.Debugf("Invalid workflow event. err=%v", err)
+		http.Error(w, "Expected JSON object with workflowId", http.StatusBadRequest)
+		return
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	if !source.running {
+		http.Error(w, "Not the leader", http.StatusServiceUnavailable)
+		return
+	}
+	if !source.pending[event.WorkflowID] {
+		if len(source.pending) >= maxPendingWebhookEvents {
+			logrus.Warnf("Rejecting event of workflow %s, too many pending workflow events", event.WorkflowID)
+			workflowEventsCounter.WithLabelValues("REJECTED").Inc()
+			http.Error(w, "Too many pending workflow events", http.StatusTooManyRequests)
+			return
+		}
+		source.pending[event.WorkflowID] = true
+		source.events <- event.WorkflowID
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
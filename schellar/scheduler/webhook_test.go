@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frinx/schellar/ifc"
+)
+
+// waitFor polls condition, events are handled in background
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func postEvent(source *WebhookEventSource, body string) int {
+	return postAuthorizedEvent(source, body, "")
+}
+
+func postAuthorizedEvent(source *WebhookEventSource, body string, authorization string) int {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	source.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestWebhookEventTriggersDownstreamSchedule(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	upstream := newTestSchedule("upstream")
+	db.Insert(upstream)
+	downstream := newTestSchedule("downstream")
+	downstream.Status = ""
+	downstream.CronString = ""
+	downstream.TriggerMode = ifc.TriggerModeUpstream
+	downstream.DependsOn = []ifc.Dependency{{ScheduleName: "upstream", Condition: ifc.DependencyCompleted}}
+	db.Insert(downstream)
+	addRunningExecution(db, conductor, upstream.Name, "wf-upstream", time.Now().Add(-time.Minute))
+
+	source := NewWebhookEventSource()
+	if code := postEvent(source, `{"workflowId":"wf-upstream"}`); code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected events rejected before the source runs, got %d", code)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go source.Run(HandleWorkflowEvent, stop)
+	waitFor(t, func() bool { return postEvent(source, `{}`) != http.StatusServiceUnavailable })
+
+	if code := postEvent(source, `not json`); code != http.StatusBadRequest {
+		t.Errorf("Expected bad request for invalid event, got %d", code)
+	}
+	if code := postEvent(source, `{}`); code != http.StatusBadRequest {
+		t.Errorf("Expected bad request for event without workflowId, got %d", code)
+	}
+
+	conductor.finish("wf-upstream", "COMPLETED", nil)
+	if code := postEvent(source, `{"workflowId":"wf-upstream"}`); code != http.StatusAccepted {
+		t.Fatalf("Unexpected response to workflow event %d", code)
+	}
+	waitFor(t, func() bool { return db.schedule("upstream").Status == "COMPLETED" })
+	waitFor(t, func() bool { return db.schedule("downstream").Status == "RUNNING" })
+	execution := db.executionsOf("downstream")[0]
+	if execution.Trigger != TriggerUpstream || execution.WorkflowID != "wf-1" || execution.Status != "RUNNING" {
+		t.Errorf("Unexpected downstream execution %+v", execution)
+	}
+}
+
+func TestWebhookEventOfUnknownWorkflow(t *testing.T) {
+	db := newFakeDB()
+	conductor := newFakeConductor()
+	setupTest(t, db, conductor.ServeHTTP)
+	db.Insert(newTestSchedule("upstream"))
+	addRunningExecution(db, conductor, "upstream", "wf-upstream", time.Now().Add(-time.Minute))
+	conductor.finish("wf-upstream", "COMPLETED", nil)
+
+	HandleWorkflowEvent("wf-other")
+	if db.schedule("upstream").Status != "RUNNING" {
+		t.Errorf("Event of unknown workflow refreshed schedule %s", db.schedule("upstream").Status)
+	}
+}
+
+func TestWebhookEventSecret(t *testing.T) {
+	setupTest(t, newFakeDB(), nil)
+	Configuration.EventsSecret = "s3cret"
+	handled := make(chan string, 10)
+	source := NewWebhookEventSource()
+	stop := make(chan struct{})
+	defer close(stop)
+	go source.Run(func(workflowID string) { handled <- workflowID }, stop)
+	waitFor(t, func() bool { return postAuthorizedEvent(source, `{}`, "Bearer s3cret") != http.StatusServiceUnavailable })
+
+	cases := map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusAccepted,
+	}
+	for authorization, expected := range cases {
+		if code := postAuthorizedEvent(source, `{"workflowId":"wf-1"}`, authorization); code != expected {
+			t.Errorf("Unexpected response %d to event with authorization '%s'", code, authorization)
+		}
+	}
+	if workflowID := <-handled; workflowID != "wf-1" || len(handled) != 0 {
+		t.Errorf("Expected only the authorized event handled")
+	}
+}
+
+func TestWebhookEventsCoalesced(t *testing.T) {
+	setupTest(t, newFakeDB(), nil)
+	handling := make(chan string)
+	proceed := make(chan struct{})
+	source := NewWebhookEventSource()
+	stop := make(chan struct{})
+	defer close(stop)
+	go source.Run(func(workflowID string) {
+		handling <- workflowID
+		<-proceed
+	}, stop)
+	waitFor(t, func() bool { return postEvent(source, `{}`) != http.StatusServiceUnavailable })
+
+	postEvent(source, `{"workflowId":"wf-busy"}`)
+	<-handling
+	// events received while the worker is busy wait, repeated ones are merged
+	for i := 0; i < 5; i++ {
+		if code := postEvent(source, `{"workflowId":"wf-1"}`); code != http.StatusAccepted {
+			t.Fatalf("Unexpected response to workflow event %d", code)
+		}
+	}
+	for i := 2; i <= maxPendingWebhookEvents; i++ {
+		postEvent(source, fmt.Sprintf(`{"workflowId":"wf-%d"}`, i))
+	}
+	if code := postEvent(source, `{"workflowId":"wf-overflow"}`); code != http.StatusTooManyRequests {
+		t.Fatalf("Expected event rejected when too many events are pending, got %d", code)
+	}
+	if code := postEvent(source, `{"workflowId":"wf-1"}`); code != http.StatusAccepted {
+		t.Fatalf("Expected event of pending workflow accepted, got %d", code)
+	}
+
+	close(proceed)
+	handled := map[string]int{}
+	for i := 0; i < maxPendingWebhookEvents; i++ {
+		handled[<-handling]++
+	}
+	if len(handled) != maxPendingWebhookEvents || handled["wf-1"] != 1 {
+		t.Fatalf("Expected every pending workflow handled once, got %d workflows, wf-1 %d times", len(handled), handled["wf-1"])
+	}
+}